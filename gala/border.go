@@ -0,0 +1,204 @@
+package gala
+
+import "image/color"
+
+// BorderStyle is how the sides of a border are drawn.
+type BorderStyle int8
+
+const (
+	BorderSolid BorderStyle = iota
+	BorderDashed
+	BorderDotted
+)
+
+// BorderSide is one side of a Border, it has no border when Width is 0.
+type BorderSide struct {
+	Width int16
+	Color color.RGBA
+	Style BorderStyle
+}
+
+/*
+Border of a box, it's drawn inside the rect of the box and takes room
+like padding does: the content is placed inside the border and the
+padding, and boxes sized by their content grow by its widths.
+*/
+type Border struct {
+	Left, Top, Right, Bottom BorderSide
+}
+
+// the sides in the order of the setters and of CSS shorthands
+func (b *Border) sides() [4]*BorderSide {
+	return [4]*BorderSide{&b.Top, &b.Right, &b.Bottom, &b.Left}
+}
+
+func (b Border) visible() bool {
+	return b.Left.Width > 0 || b.Top.Width > 0 || b.Right.Width > 0 || b.Bottom.Width > 0
+}
+
+// Border sets the width and color of all sides.
+func (b *Box) Border(width int16, col color.RGBA) *Box {
+	return b.BorderTop(width, col).
+		BorderRight(width, col).
+		BorderBottom(width, col).
+		BorderLeft(width, col)
+}
+
+func (b *Box) BorderTop(width int16, col color.RGBA) *Box {
+	b.border.Top.Width, b.border.Top.Color = max(0, width), col
+	return b
+}
+
+func (b *Box) BorderRight(width int16, col color.RGBA) *Box {
+	b.border.Right.Width, b.border.Right.Color = max(0, width), col
+	return b
+}
+
+func (b *Box) BorderBottom(width int16, col color.RGBA) *Box {
+	b.border.Bottom.Width, b.border.Bottom.Color = max(0, width), col
+	return b
+}
+
+func (b *Box) BorderLeft(width int16, col color.RGBA) *Box {
+	b.border.Left.Width, b.border.Left.Color = max(0, width), col
+	return b
+}
+
+// Border styles, they apply to all sides, or to one with the setters below
+
+func (b *Box) BorderStyle_Solid() *Box {
+	return b.borderStyle(BorderSolid)
+}
+
+func (b *Box) BorderStyle_Dashed() *Box {
+	return b.borderStyle(BorderDashed)
+}
+
+func (b *Box) BorderStyle_Dotted() *Box {
+	return b.borderStyle(BorderDotted)
+}
+
+func (b *Box) borderStyle(style BorderStyle) *Box {
+	return b.BorderTopStyle(style).
+		BorderRightStyle(style).
+		BorderBottomStyle(style).
+		BorderLeftStyle(style)
+}
+
+func (b *Box) BorderTopStyle(style BorderStyle) *Box {
+	b.border.Top.Style = style
+	return b
+}
+
+func (b *Box) BorderRightStyle(style BorderStyle) *Box {
+	b.border.Right.Style = style
+	return b
+}
+
+func (b *Box) BorderBottomStyle(style BorderStyle) *Box {
+	b.border.Bottom.Style = style
+	return b
+}
+
+func (b *Box) BorderLeftStyle(style BorderStyle) *Box {
+	b.border.Left.Style = style
+	return b
+}
+
+// insetBorder moves the content inside the border, by adding its widths to
+// the padding. From here on the padding is where the content starts.
+func (b *Box) insetBorder() {
+	b.padding.left += b.border.Left.Width
+	b.padding.top += b.border.Top.Width
+	b.padding.right += b.border.Right.Width
+	b.padding.bottom += b.border.Bottom.Width
+}
+
+// Style setters, they match the ones of Box
+
+func (s Style) Border(width int16, col color.RGBA) Style {
+	return s.BorderTop(width, col).
+		BorderRight(width, col).
+		BorderBottom(width, col).
+		BorderLeft(width, col)
+}
+
+func (s Style) BorderTop(width int16, col color.RGBA) Style {
+	s.border.Top.Width, s.border.Top.Color, s.set = max(0, width), col, s.set|styleBorderTop
+	return s
+}
+
+func (s Style) BorderRight(width int16, col color.RGBA) Style {
+	s.border.Right.Width, s.border.Right.Color, s.set = max(0, width), col, s.set|styleBorderRight
+	return s
+}
+
+func (s Style) BorderBottom(width int16, col color.RGBA) Style {
+	s.border.Bottom.Width, s.border.Bottom.Color, s.set = max(0, width), col, s.set|styleBorderBottom
+	return s
+}
+
+func (s Style) BorderLeft(width int16, col color.RGBA) Style {
+	s.border.Left.Width, s.border.Left.Color, s.set = max(0, width), col, s.set|styleBorderLeft
+	return s
+}
+
+func (s Style) BorderStyle_Solid() Style {
+	return s.borderStyle(BorderSolid)
+}
+
+func (s Style) BorderStyle_Dashed() Style {
+	return s.borderStyle(BorderDashed)
+}
+
+func (s Style) BorderStyle_Dotted() Style {
+	return s.borderStyle(BorderDotted)
+}
+
+func (s Style) borderStyle(style BorderStyle) Style {
+	return s.BorderTopStyle(style).
+		BorderRightStyle(style).
+		BorderBottomStyle(style).
+		BorderLeftStyle(style)
+}
+
+func (s Style) BorderTopStyle(style BorderStyle) Style {
+	s.border.Top.Style, s.set = style, s.set|styleBorderTopStyle
+	return s
+}
+
+func (s Style) BorderRightStyle(style BorderStyle) Style {
+	s.border.Right.Style, s.set = style, s.set|styleBorderRightStyle
+	return s
+}
+
+func (s Style) BorderBottomStyle(style BorderStyle) Style {
+	s.border.Bottom.Style, s.set = style, s.set|styleBorderBottomStyle
+	return s
+}
+
+func (s Style) BorderLeftStyle(style BorderStyle) Style {
+	s.border.Left.Style, s.set = style, s.set|styleBorderLeftStyle
+	return s
+}
+
+// the properties of the sides, in the order of Border.sides
+var borderProperties = [4]struct{ side, style styleProperty }{
+	{styleBorderTop, styleBorderTopStyle},
+	{styleBorderRight, styleBorderRightStyle},
+	{styleBorderBottom, styleBorderBottomStyle},
+	{styleBorderLeft, styleBorderLeftStyle},
+}
+
+// copyBorder copies the sides, and the styles of the sides, that are set
+func (s *Style) copyBorder(dst *Border) {
+	from, to := s.border.sides(), dst.sides()
+	for i, p := range borderProperties {
+		if s.has(p.side) {
+			to[i].Width, to[i].Color = from[i].Width, from[i].Color
+		}
+		if s.has(p.style) {
+			to[i].Style = from[i].Style
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package gala
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestBorderSideStyles(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	tests := []struct {
+		name string
+		box  func(l *Layout) *Box
+		want [4]BorderStyle // top, right, bottom, left
+	}{
+		{"all sides", func(l *Layout) *Box {
+			return l.Box().Border(1, white).BorderStyle_Dashed()
+		}, [4]BorderStyle{BorderDashed, BorderDashed, BorderDashed, BorderDashed}},
+		{"one side over all", func(l *Layout) *Box {
+			return l.Box().Border(1, white).BorderStyle_Dashed().BorderTopStyle(BorderDotted)
+		}, [4]BorderStyle{BorderDotted, BorderDashed, BorderDashed, BorderDashed}},
+		{"a style only sets its side", func(l *Layout) *Box {
+			return l.Box().Border(1, white).BorderStyle_Dashed().Apply(Style{}.BorderLeftStyle(BorderDotted))
+		}, [4]BorderStyle{BorderDashed, BorderDashed, BorderDashed, BorderDotted}},
+		{"each side", func(l *Layout) *Box {
+			return l.Box().BorderRightStyle(BorderDotted).BorderBottomStyle(BorderDashed)
+		}, [4]BorderStyle{BorderSolid, BorderDotted, BorderDashed, BorderSolid}},
+	}
+	for _, test := range tests {
+		l, r := newTestLayout()
+		box := test.box(l)
+		l.End(r)
+		b := box.border
+		if got := [4]BorderStyle{b.Top.Style, b.Right.Style, b.Bottom.Style, b.Left.Style}; got != test.want {
+			t.Errorf("%s: styles %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -9,8 +9,15 @@ func newBox() Box {
 }
 
 type Box struct {
-	inUse bool
-	id    string
+	layout *Layout
+	inUse  bool
+	id     string
+	hash   ID
+	class  string // space separated, for stylesheets
+
+	// the id this pool slot resolved to on the previous frame
+	prevHash ID
+	prevId   string
 
 	x, y int16
 
@@ -19,12 +26,48 @@ type Box struct {
 	children []*Box
 
 	onHover func(box *Box)
+	capture bool
+
+	focusable bool
+	tabIndex  int16
+	onFocus   func(box *Box)
+	onBlur    func(box *Box)
+	onKeyDown func(box *Box, key Key)
+	nav       [4]*Box // explicit navigation targets, indexed by navDirection
+
+	// overlays are placed next to their anchor
+	anchor  *Box
+	side    Side
+	atPoint bool // placed at point rather than next to the anchor
+	point   Rect
+	layer   int16 // painted above lower layers, regardless of z-index
+	// ignored by the pointer, like tooltips
+	passThrough bool
+	modal       *Box // the backdrop of the modal it's in
+	scrollable  bool // takes the mouse wheel
+	onClose     func()
+	disabled    bool
+
+	text string
+	clip Rect // drawing is clipped to this rect
+	// custom drawing, after the background and text
+	paint func(box *Box, renderer Renderer)
+	// called every frame after the layout is calculated
+	update func(box *Box)
+	// called while the box has focus, before keys are used for navigation
+	onInput func(box *Box)
 	baseStyle
+	// the style before the layout was calculated, for snapshots
+	declared baseStyle
 }
 
 // Appends a child to the parent's list of children
 func (b *Box) Contains(children ...*Box) *Box {
 	for _, child := range children {
+		if child.anchor != nil {
+			// overlays are laid out on their own
+			continue
+		}
 		child.parent = b
 		b.children = append(b.children, child)
 	}
@@ -34,10 +77,34 @@ func (b *Box) reset() {
 
 	b.inUse = false
 	b.id = ""
+	b.hash = 0
+	b.class = ""
 	b.x = 0
 	b.y = 0
 	b.parent = nil
 	b.children = b.children[:0]
+	b.onHover = nil
+	b.capture = false
+
+	b.focusable = false
+	b.tabIndex = 0
+	b.onFocus = nil
+	b.onBlur = nil
+	b.onKeyDown = nil
+	b.nav = [4]*Box{}
+	b.anchor = nil
+	b.side = SideBottom
+	b.atPoint = false
+	b.layer = 0
+	b.passThrough = false
+	b.modal = nil
+	b.scrollable = false
+	b.onClose = nil
+	b.disabled = false
+	b.text = ""
+	b.paint = nil
+	b.update = nil
+	b.onInput = nil
 
 	// reset baseStyle
 	b.Size(0, 0)
@@ -49,12 +116,17 @@ func (b *Box) reset() {
 	b.top = 0
 	b.bottom = 0
 	b.alignBits = 0
+	b.radius = 0
+	b.border = Border{}
 	b.Padding(0).
 		Margin(0).
 		Position_Relative().
 		Display_Flex().
 		JustifyContent_FlexStart().
-		AlignItems_Stretch()
+		AlignItems_Stretch().
+		Overflow_Visible().
+		FontSize(defaultFontSize).
+		TextColor(color.RGBA{0, 0, 0, 255})
 
 }
 
@@ -170,22 +242,22 @@ func (b *Box) AlignSelf_Stretch() *Box {
 // Align Items
 
 func (b *Box) AlignItems_FlexStart() *Box {
-	b.alignSelf(alignFlexStart)
+	b.alignItems(alignFlexStart)
 	return b
 }
 
 func (b *Box) AlignItems_Center() *Box {
-	b.alignSelf(alignCenter)
+	b.alignItems(alignCenter)
 	return b
 }
 
 func (b *Box) AlignItems_FlexEnd() *Box {
-	b.alignSelf(alignFlexEnd)
+	b.alignItems(alignFlexEnd)
 	return b
 }
 
 func (b *Box) AlignItems_Stretch() *Box {
-	b.alignSelf(alignStretch)
+	b.alignItems(alignStretch)
 	return b
 }
 
@@ -243,12 +315,12 @@ func (b *Box) alignSelf(align flexAlign) *Box {
 		k = selfAlignStretch
 	}
 	//unset any previous
-	b.alignBits.
+	b.alignBits = b.alignBits.
 		unset(selfAlignCenter).
 		unset(selfAlignFlexEnd).
 		unset(selfAlignFlexStart).
-		unset(selfAlignStretch)
-	b.alignBits.set(k)
+		unset(selfAlignStretch).
+		set(k)
 	return b
 
 }
@@ -273,12 +345,12 @@ func (b *Box) alignItems(align flexAlign) *Box {
 		k = itemsAlignStretch
 	}
 	//unset any previous
-	b.alignBits.
+	b.alignBits = b.alignBits.
 		unset(itemsAlignCenter).
 		unset(itemsAlignFlexEnd).
 		unset(itemsAlignFlexStart).
-		unset(itemsAlignStretch)
-	b.alignBits.set(k)
+		unset(itemsAlignStretch).
+		set(k)
 	return b
 }
 
@@ -343,9 +415,12 @@ func (b *Box) Bottom(i int16) *Box {
 negative numbers between 0 and 1 are used for percentage
 
 	example: -0.5 = 50%
+	use gala.Percent() as a helper function
+
+anything below -1 is 100%, sizes are never negative otherwise
 */
 func (b *Box) Width(i float32) *Box {
-	b.width = max(0, i)
+	b.width = max(-1, i)
 	return b
 }
 
@@ -354,9 +429,11 @@ negative numbers between 0 and 1 are used for percentage
 
 	example: -0.5 = 50%
 	use gala.Percent() as a helper function
+
+anything below -1 is 100%, sizes are never negative otherwise
 */
 func (b *Box) Height(i float32) *Box {
-	b.height = max(0, i)
+	b.height = max(-1, i)
 	return b
 }
 
@@ -365,10 +442,11 @@ negative numbers between 0 and 1 are used for percentage
 
 	example: -0.5 = 50%
 	use gala.Percent() as a helper function
+
+sizes are clamped like the ones of Width and Height
 */
 func (b *Box) Size(w, h float32) *Box {
-	b.width, b.height = w, h
-	return b
+	return b.Width(w).Height(h)
 }
 
 func (b *Box) BackgroundColor(col color.RGBA) *Box {
@@ -386,18 +464,52 @@ func (b *Box) Id(i string) *Box {
 	return b
 }
 
+// Class sets the classes of the box, separated by spaces,
+// stylesheets select boxes by them.
+func (b *Box) Class(names string) *Box {
+	b.class = names
+	return b
+}
+
+// BorderRadius rounds the corners of the background, when the renderer
+// can draw rounded rects.
+func (b *Box) BorderRadius(i int16) *Box {
+	b.radius = max(0, i)
+	return b
+}
+
+/*
+ID returns the hashed id of the box.
+
+It is resolved during End. Before that, a box with an Id that no other box
+had on the previous frame gets the id that box had. Otherwise it's the id
+this pool slot had on the previous frame, if the slot had the same Id,
+which is stable as long as the tree is declared in the same order.
+0 means the box has no id yet.
+*/
+func (b *Box) ID() ID {
+	if b.hash != 0 {
+		return b.hash
+	}
+	if b.id != "" && b.layout != nil {
+		if id := b.layout.lastIds[b.id]; id != 0 {
+			return id
+		}
+	}
+	if b.prevId == b.id {
+		return b.prevHash
+	}
+	return 0
+}
+
 func (b *Box) Flex(i int16) *Box {
 	b.flex = i
 	return b
 }
 
+// Hovered calls onHover at End while the box, or one of its children,
+// is the top most box under the mouse.
 func (b *Box) Hovered(onHover func(box *Box)) *Box {
 	b.onHover = onHover
 	return b
 }
-
-func (b *Box) pointIsInside(x, y int32) bool {
-	return b.x <= int16(x) && b.x+int16(b.width) >= int16(x) &&
-		b.y <= int16(y) && b.y+int16(b.height) >= int16(y)
-
-}
@@ -0,0 +1,57 @@
+package gala
+
+import "time"
+
+const defaultTooltipDelay = 500 * time.Millisecond
+
+// ticks the frame clock, called when a frame begins
+func (l *Layout) tick() {
+	l.frame++
+	if l.clock != nil {
+		l.now = l.clock()
+	} else {
+		l.now = time.Now()
+	}
+}
+
+// UseClock replaces time.Now as the source of the frame clock,
+// so timing can be controlled in tests and replays.
+func (l *Layout) UseClock(clock func() time.Time) {
+	l.clock = clock
+}
+
+// Now returns the time the current frame began.
+func (l *Layout) Now() time.Time {
+	return l.now
+}
+
+// Frame returns how many frames have begun.
+func (l *Layout) Frame() uint64 {
+	return l.frame
+}
+
+// remember since when the hovered boxes are hovered
+func (l *Layout) trackHoverTime() {
+	if l.hoverSince == nil {
+		l.hoverSince = make(map[ID]time.Time)
+	}
+	for id := range l.hoverSince {
+		if !l.Hovered(id) {
+			delete(l.hoverSince, id)
+		}
+	}
+	for _, id := range l.hoveredIds {
+		if _, ok := l.hoverSince[id]; !ok {
+			l.hoverSince[id] = l.now
+		}
+	}
+}
+
+// HoveredFor returns for how long the box with the id has been hovered, 0 if it isn't.
+func (l *Layout) HoveredFor(id ID) time.Duration {
+	since, ok := l.hoverSince[id]
+	if !ok {
+		return 0
+	}
+	return l.now.Sub(since)
+}
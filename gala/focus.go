@@ -0,0 +1,197 @@
+package gala
+
+import "sort"
+
+// Focusable lets the box receive keyboard focus.
+func (b *Box) Focusable() *Box {
+	b.focusable = true
+	return b
+}
+
+/*
+TabIndex makes the box focusable and changes its place in the Tab order.
+
+	positive: visited first, in ascending order
+	0: visited in tree order (the default)
+	negative: can be focused, but is skipped by Tab
+*/
+func (b *Box) TabIndex(i int16) *Box {
+	b.focusable = true
+	b.tabIndex = i
+	return b
+}
+
+// called when the box gains keyboard focus
+func (b *Box) OnFocus(onFocus func(box *Box)) *Box {
+	b.onFocus = onFocus
+	return b
+}
+
+// called when the box loses keyboard focus
+func (b *Box) OnBlur(onBlur func(box *Box)) *Box {
+	b.onBlur = onBlur
+	return b
+}
+
+// called for every key pressed while the box has focus
+func (b *Box) OnKeyDown(onKeyDown func(box *Box, key Key)) *Box {
+	b.onKeyDown = onKeyDown
+	return b
+}
+
+// Focus moves the keyboard focus to the box with the id, 0 clears it.
+// OnBlur and OnFocus are called during the next End.
+func (l *Layout) Focus(id ID) {
+	l.pendingFocus = id
+	l.hasPendingFocus = true
+}
+
+// Focused reports whether the box with the id has keyboard focus.
+func (l *Layout) Focused(id ID) bool {
+	return id != 0 && l.focusedId == id
+}
+
+// FocusedID returns the id of the box that has keyboard focus, 0 if none.
+func (l *Layout) FocusedID() ID {
+	return l.focusedId
+}
+
+// builds the Tab order out of the focusable boxes, which are in tree order.
+func (l *Layout) sortTabOrder() {
+	l.tabOrder = l.tabOrder[:0]
+	for _, p := range l.focusable {
+		if p.tabIndex >= 0 {
+			l.tabOrder = append(l.tabOrder, p)
+		}
+	}
+	sort.SliceStable(l.tabOrder, func(i, j int) bool {
+		a, b := l.tabOrder[i].tabIndex, l.tabOrder[j].tabIndex
+		// 0 goes after every positive tab index
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
+}
+
+// finds a focusable box of this frame by its id
+func (l *Layout) focusableBox(id ID) *Box {
+	if id == 0 {
+		return nil
+	}
+	for _, p := range l.focusable {
+		if p.hash == id {
+			return p
+		}
+	}
+	return nil
+}
+
+// setFocus moves the focus, calling OnBlur and OnFocus.
+func (l *Layout) setFocus(next *Box) {
+	var nextId ID
+	if next != nil {
+		nextId = next.hash
+	}
+	if nextId == l.focusedId {
+		return
+	}
+	if prev := l.focusableBox(l.focusedId); prev != nil && prev.onBlur != nil {
+		prev.onBlur(prev)
+	}
+	l.focusedId = nextId
+	if next != nil && next.onFocus != nil {
+		next.onFocus(next)
+	}
+}
+
+// moves focus to the next (or previous) box in the Tab order.
+func (l *Layout) focusNext(backwards bool) {
+	if len(l.tabOrder) == 0 {
+		return
+	}
+	current := -1
+	for i, p := range l.tabOrder {
+		if p.hash == l.focusedId {
+			current = i
+			break
+		}
+	}
+	var next int
+	switch {
+	case current == -1 && backwards:
+		next = len(l.tabOrder) - 1
+	case current == -1:
+		next = 0
+	case backwards:
+		next = (current - 1 + len(l.tabOrder)) % len(l.tabOrder)
+	default:
+		next = (current + 1) % len(l.tabOrder)
+	}
+	l.setFocus(l.tabOrder[next])
+}
+
+// handleFocus applies pending focus changes, Tab and arrow key navigation
+// and sends the keys to the focused box.
+func (l *Layout) handleFocus() {
+	l.sortTabOrder()
+	if l.hasPendingFocus {
+		l.hasPendingFocus = false
+		l.setFocus(l.focusableBox(l.pendingFocus))
+	}
+	if l.modal != nil && l.focusableBox(l.focusedId) == nil {
+		// a modal was shown, move the focus into it
+		l.focusNext(false)
+	}
+
+	if focused := l.focusableBox(l.focusedId); focused != nil && focused.onInput != nil {
+		focused.onInput(focused)
+	}
+
+	for _, key := range l.keys {
+		if key == KeyTab {
+			l.focusNext(l.ShiftDown())
+			continue
+		}
+		focused := l.focusableBox(l.focusedId)
+		if focused != nil && focused.onKeyDown != nil {
+			focused.onKeyDown(focused, key)
+		}
+	}
+	l.handleNavigation()
+}
+
+// clicking a box focuses it or its nearest focusable parent,
+// clicking anywhere else clears the focus, unless a modal is shown.
+// The parent of an overlay is its anchor.
+func (l *Layout) handlePointerFocus(list []*Box) {
+	if !l.MousePressed(MouseLeft) {
+		return
+	}
+	x, y := l.MousePos()
+	target := boxAt(list, x, y)
+	for target != nil && !target.focusable {
+		if target.anchor != nil {
+			// overlays belong to their anchor
+			target = target.anchor
+		} else {
+			target = target.parent
+		}
+	}
+	if target == nil && l.modal != nil {
+		return
+	}
+	l.setFocus(target)
+}
+
+// boxAt returns the top most box under the point, skipping pass through boxes.
+// list must be sorted in drawing order.
+func boxAt(list []*Box, x, y int32) *Box {
+	for i := len(list) - 1; i >= 0; i-- {
+		p := list[i]
+		if !p.passThrough && p.Rect().Contains(x, y) && p.clip.Contains(x, y) {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package gala
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTabOrder(t *testing.T) {
+	l, r := newTestLayout()
+	in := &testInput{}
+	l.UseInput(in)
+	var boxes map[string]*Box
+	declare := func() {
+		boxes = map[string]*Box{
+			"first":    l.Box().Id("first").Focusable(),
+			"second":   l.Box().Id("second").TabIndex(2),
+			"third":    l.Box().Id("third").TabIndex(1),
+			"skipped":  l.Box().Id("skipped").TabIndex(-1),
+			"disabled": l.Box().Id("disabled").Focusable().Disabled(),
+			"last":     l.Box().Id("last").Focusable(),
+		}
+		l.Box().Contains(boxes["first"], boxes["second"], boxes["third"], boxes["skipped"],
+			boxes["disabled"], l.Box().Contains(boxes["last"]))
+	}
+	declare()
+	l.End(r)
+	focused := func() string {
+		for name, box := range boxes {
+			if l.Focused(box.ID()) {
+				return name
+			}
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name  string
+		shift bool
+		want  []string
+	}{
+		{"forward", false, []string{"third", "second", "first", "last", "third"}},
+		{"backwards", true, []string{"last", "first", "second", "third", "last"}},
+	}
+	for _, test := range tests {
+		l.Focus(0)
+		declare()
+		l.End(r)
+		in.down = nil
+		if test.shift {
+			in.down = []Key{KeyLeftShift}
+		}
+		var got []string
+		for range test.want {
+			in.keys = []Key{KeyTab}
+			declare()
+			l.End(r)
+			got = append(got, focused())
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: focused %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFocusOnClick(t *testing.T) {
+	l, r := newTestLayout()
+	in := &testInput{}
+	l.UseInput(in)
+	var events []string
+	var field, label, other *Box
+	declare := func() {
+		label = l.Box().Size(50, 20)
+		field = l.Box().Id("field").Focusable().Size(100, 20).Contains(label).
+			OnFocus(func(*Box) { events = append(events, "focus field") }).
+			OnBlur(func(*Box) { events = append(events, "blur field") })
+		other = l.Box().Id("other").Focusable().Size(100, 20).
+			OnFocus(func(*Box) { events = append(events, "focus other") })
+		l.Box().Size(400, 100).FlexDirection_Column().Contains(field, other)
+	}
+
+	tests := []struct {
+		name   string
+		x, y   int32
+		want   func() *Box
+		events []string
+	}{
+		{"a child focuses its focusable parent", 10, 10, func() *Box { return field }, []string{"focus field"}},
+		{"another box", 10, 30, func() *Box { return other }, []string{"blur field", "focus other"}},
+		{"the same box again", 10, 30, func() *Box { return other }, nil},
+		{"nothing focusable clears it", 300, 80, func() *Box { return nil }, nil},
+	}
+	for _, test := range tests {
+		events = nil
+		click(l, r, in, test.x, test.y, declare)
+		var want ID
+		if box := test.want(); box != nil {
+			want = box.ID()
+		}
+		if got := l.FocusedID(); got != want {
+			t.Errorf("%s: focused %d, want %d", test.name, got, want)
+		}
+		if !slices.Equal(events, test.events) {
+			t.Errorf("%s: events %v, want %v", test.name, events, test.events)
+		}
+	}
+}
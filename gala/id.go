@@ -0,0 +1,79 @@
+package gala
+
+import "log"
+
+// ID identifies a box across frames.
+// It is hashed from the parent's ID plus either the box's Id,
+// or its index among its siblings when no Id was given.
+type ID uint32
+
+const (
+	fnvOffset uint32 = 2166136261
+	fnvPrime  uint32 = 16777619
+)
+
+// used to keep an Id of "1" apart from the child at index 1
+const (
+	idTagString byte = iota + 1
+	idTagIndex
+)
+
+func hashByte(h uint32, b byte) uint32 {
+	h ^= uint32(b)
+	h *= fnvPrime
+	return h
+}
+
+// hashString combines the parent ID with a user supplied Id (FNV-1a)
+func hashString(parent ID, s string) ID {
+	h := fnvOffset ^ uint32(parent)
+	h = hashByte(h, idTagString)
+	for i := 0; i < len(s); i++ {
+		h = hashByte(h, s[i])
+	}
+	return nonZero(h)
+}
+
+// hashIndex combines the parent ID with a child index (FNV-1a)
+func hashIndex(parent ID, index int) ID {
+	h := fnvOffset ^ uint32(parent)
+	h = hashByte(h, idTagIndex)
+	for i := 0; i < 4; i++ {
+		h = hashByte(h, byte(index>>(8*i)))
+	}
+	return nonZero(h)
+}
+
+// 0 is reserved for "no ID"
+func nonZero(h uint32) ID {
+	if h == 0 {
+		return 1
+	}
+	return ID(h)
+}
+
+// resolve the ID of every child of the element.
+// in debug mode, siblings that hash to the same ID are reported,
+// once for as long as they keep showing up.
+func (l *Layout) resolveChildIds(element *Box) {
+	if l.debug {
+		clear(l.seenIds)
+	}
+	for i, p := range element.children {
+		if p.id != "" {
+			p.hash = hashString(element.hash, p.id)
+		} else {
+			p.hash = hashIndex(element.hash, i)
+		}
+		if !l.debug {
+			continue
+		}
+		if _, ok := l.seenIds[p.hash]; ok {
+			if _, reported := l.reportedIds[p.hash]; !reported {
+				log.Printf("gala: duplicate id %q inside %q (child %d)", p.id, element.id, i)
+			}
+			l.reportedIds[p.hash] = l.frame
+		}
+		l.seenIds[p.hash] = struct{}{}
+	}
+}
@@ -0,0 +1,120 @@
+package gala
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestIdsAreStable(t *testing.T) {
+	l, r := newTestLayout()
+	declare := func() (named, indexed, nested *Box) {
+		named = l.Box().Id("Named")
+		indexed = l.Box()
+		nested = l.Box()
+		l.Box().Id("Parent").Contains(l.Box(), nested)
+		return
+	}
+	named, indexed, nested := declare()
+	l.End(r)
+	first := [3]ID{named.hash, indexed.hash, nested.hash}
+	for frame := range 3 {
+		named, indexed, nested := declare()
+		l.End(r)
+		if got := [3]ID{named.hash, indexed.hash, nested.hash}; got != first {
+			t.Fatalf("frame %d: ids %v, want the ones of the first frame %v", frame, got, first)
+		}
+	}
+
+	root := l.rootBox.hash
+	tests := []struct {
+		name string
+		a, b ID
+	}{
+		{"an Id and the same index", hashString(root, "1"), hashIndex(root, 1)},
+		{"the same Id in other parents", hashString(root, "a"), hashString(hashString(root, "b"), "a")},
+		{"the same index in other parents", hashIndex(root, 0), hashIndex(hashIndex(root, 1), 0)},
+		{"neighbouring indexes", hashIndex(root, 1), hashIndex(root, 256)},
+	}
+	for _, test := range tests {
+		if test.a == test.b {
+			t.Errorf("%s hash to the same id %d", test.name, test.a)
+		}
+	}
+	if got, want := hashString(root, "Named"), first[0]; got != want {
+		t.Errorf("Id hashes to %d, the box has %d", got, want)
+	}
+}
+
+func TestDuplicateIds(t *testing.T) {
+	var logged bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logged)
+
+	l, r := newTestLayout()
+	l.DebugMode(true)
+	frame := func(duplicates bool) {
+		second := "b"
+		if duplicates {
+			second = "a"
+		}
+		l.Box().Id("List").Contains(l.Box().Id("a"), l.Box().Id(second))
+		l.End(r)
+	}
+	reports := func() int {
+		return strings.Count(logged.String(), "duplicate id")
+	}
+
+	frame(true)
+	if !strings.Contains(logged.String(), `gala: duplicate id "a" inside "List" (child 1)`) {
+		t.Fatalf("logged %q, want the duplicate reported", logged.String())
+	}
+	for range 10 {
+		frame(true)
+	}
+	if reports() != 1 {
+		t.Errorf("reported %d times while the duplicate stayed, want once", reports())
+	}
+	for range 2 * stateFrames {
+		frame(false)
+	}
+	if len(l.reportedIds) != 0 {
+		t.Errorf("%d reported ids kept after they were gone for %d frames", len(l.reportedIds), 2*stateFrames)
+	}
+	frame(true)
+	if reports() != 2 {
+		t.Errorf("reported %d times, want the duplicate reported again once it came back", reports())
+	}
+}
+
+func TestIdsBeforeEnd(t *testing.T) {
+	l, r := newTestLayout()
+	declare := func(extra bool) (named, twin *Box) {
+		if extra {
+			l.Box()
+		}
+		named = l.Box().Id("Named")
+		twin = l.Box().Id("Twin")
+		l.Box().Id("Parent").Contains(l.Box().Id("Twin"))
+		return
+	}
+	named, twin := declare(false)
+	l.End(r)
+	want := [2]ID{named.hash, twin.hash}
+
+	named, twin = declare(true)
+	if got := named.ID(); got != want[0] {
+		t.Errorf("a unique Id is %d before End after the slots shifted, want %d", got, want[0])
+	}
+	if got := twin.ID(); got == want[1] {
+		t.Errorf("an Id two boxes had is %d before End after the slots shifted, want it unresolved", got)
+	}
+	l.End(r)
+
+	named, twin = declare(true)
+	if got := [2]ID{named.ID(), twin.ID()}; got != want {
+		t.Errorf("ids %v before End once the tree stayed the same, want %v", got, want)
+	}
+	l.End(r)
+}
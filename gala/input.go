@@ -0,0 +1,270 @@
+package gala
+
+import "strconv"
+
+// Key codes, they match the GLFW (and raylib) key codes.
+type Key int32
+
+const KeyNull Key = 0
+
+const (
+	KeySpace      Key = 32
+	KeyApostrophe Key = 39
+	KeyComma      Key = 44
+	KeyMinus      Key = 45
+	KeyPeriod     Key = 46
+	KeySlash      Key = 47
+)
+
+const (
+	Key0 Key = iota + 48
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+)
+
+const (
+	KeyA Key = iota + 65
+	KeyB
+	KeyC
+	KeyD
+	KeyE
+	KeyF
+	KeyG
+	KeyH
+	KeyI
+	KeyJ
+	KeyK
+	KeyL
+	KeyM
+	KeyN
+	KeyO
+	KeyP
+	KeyQ
+	KeyR
+	KeyS
+	KeyT
+	KeyU
+	KeyV
+	KeyW
+	KeyX
+	KeyY
+	KeyZ
+)
+
+const (
+	KeyEscape Key = iota + 256
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyInsert
+	KeyDelete
+	KeyRight
+	KeyLeft
+	KeyDown
+	KeyUp
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+)
+
+const (
+	KeyF1 Key = iota + 290
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+)
+
+const (
+	KeyLeftShift Key = iota + 340
+	KeyLeftControl
+	KeyLeftAlt
+	KeyLeftSuper
+	KeyRightShift
+	KeyRightControl
+	KeyRightAlt
+	KeyRightSuper
+)
+
+var keyNames = map[Key]string{
+	KeySpace: "Space", KeyApostrophe: "'", KeyComma: ",", KeyMinus: "-",
+	KeyPeriod: ".", KeySlash: "/",
+	KeyEscape: "Esc", KeyEnter: "Enter", KeyTab: "Tab", KeyBackspace: "Backspace",
+	KeyInsert: "Ins", KeyDelete: "Del", KeyRight: "Right", KeyLeft: "Left",
+	KeyDown: "Down", KeyUp: "Up", KeyPageUp: "PageUp", KeyPageDown: "PageDown",
+	KeyHome: "Home", KeyEnd: "End",
+}
+
+// String returns the name of the key as it's printed on keyboards, like "A" or "F5".
+func (k Key) String() string {
+	switch {
+	case k >= Key0 && k <= Key9, k >= KeyA && k <= KeyZ:
+		return string(rune(k))
+	case k >= KeyF1 && k <= KeyF12:
+		return "F" + strconv.Itoa(int(k-KeyF1)+1)
+	}
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	return "Key" + strconv.Itoa(int(k))
+}
+
+// Gamepad buttons, they match the raylib gamepad button codes.
+type GamepadButton int32
+
+const (
+	GamepadDpadUp GamepadButton = iota + 1
+	GamepadDpadRight
+	GamepadDpadDown
+	GamepadDpadLeft
+	GamepadFaceUp    // Y, Triangle
+	GamepadFaceRight // B, Circle
+	GamepadFaceDown  // A, Cross
+	GamepadFaceLeft  // X, Square
+)
+
+// Mouse buttons, they match the raylib mouse button codes.
+type MouseButton int32
+
+const (
+	MouseLeft MouseButton = iota
+	MouseRight
+	MouseMiddle
+)
+
+// keys that are sent again while held down
+var repeatingKeys = [...]Key{
+	KeyBackspace, KeyDelete,
+	KeyLeft, KeyRight, KeyUp, KeyDown,
+	KeyPageUp, KeyPageDown, KeyEnter,
+}
+
+// UseInput sets where the layout reads keyboard and mouse state from.
+func (l *Layout) UseInput(input Input) {
+	l.input = input
+}
+
+// UseClipboard sets the clipboard used by text inputs.
+func (l *Layout) UseClipboard(clipboard Clipboard) {
+	l.clipboard = clipboard
+}
+
+// called before the first box of a frame is declared,
+// so the keys pressed this frame are known while building the tree.
+func (l *Layout) beginFrame() {
+	if l.frameStarted {
+		return
+	}
+	l.frameStarted = true
+	l.tick()
+	l.pollWatchers()
+	l.keys = l.keys[:0]
+	l.chars = l.chars[:0]
+	l.wheel = 0
+	if l.input == nil {
+		return
+	}
+	l.wheel = l.input.MouseWheel()
+	for key := l.input.NextKey(); key != KeyNull; key = l.input.NextKey() {
+		l.keys = append(l.keys, key)
+	}
+	for _, key := range repeatingKeys {
+		if l.input.KeyRepeated(key) {
+			l.keys = append(l.keys, key)
+		}
+	}
+	for char := l.input.NextChar(); char != 0; char = l.input.NextChar() {
+		l.chars = append(l.chars, char)
+	}
+}
+
+// KeyPressed reports whether the key was pressed this frame.
+func (l *Layout) KeyPressed(key Key) bool {
+	for _, k := range l.keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyDown reports whether the key is currently held down.
+func (l *Layout) KeyDown(key Key) bool {
+	return l.input != nil && l.input.KeyDown(key)
+}
+
+// ShiftDown reports whether either shift key is held down.
+func (l *Layout) ShiftDown() bool {
+	return l.KeyDown(KeyLeftShift) || l.KeyDown(KeyRightShift)
+}
+
+// ControlDown reports whether either control key is held down.
+func (l *Layout) ControlDown() bool {
+	return l.KeyDown(KeyLeftControl) || l.KeyDown(KeyRightControl)
+}
+
+// MouseDown reports whether the mouse button is held down.
+func (l *Layout) MouseDown(button MouseButton) bool {
+	return l.input != nil && l.input.MouseDown(button)
+}
+
+// MousePressed reports whether the mouse button was pressed this frame.
+func (l *Layout) MousePressed(button MouseButton) bool {
+	return l.input != nil && l.input.MousePressed(button)
+}
+
+// MouseWheel returns how far the mouse wheel moved this frame, positive is up.
+// It's 0 once a scrolling box used it.
+func (l *Layout) MouseWheel() float32 {
+	return l.wheel
+}
+
+// MousePos returns the mouse position, read from the renderer passed to End.
+func (l *Layout) MousePos() (x, y int32) {
+	if l.renderer == nil {
+		return 0, 0
+	}
+	return l.renderer.MousePos()
+}
+
+// GamepadPressed reports whether the button of the first gamepad was pressed this frame.
+func (l *Layout) GamepadPressed(button GamepadButton) bool {
+	return l.input != nil && l.input.GamepadPressed(button)
+}
+
+// ConsumeKey removes a key pressed this frame, so the layout won't use it for
+// navigation. Widgets that handle arrow keys themselves call this.
+func (l *Layout) ConsumeKey(key Key) {
+	for i, k := range l.keys {
+		if k == key {
+			l.keys = append(l.keys[:i], l.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// consumeKeys removes the keys pressed this frame that handle returns true for.
+func (l *Layout) consumeKeys(handle func(key Key) bool) {
+	kept := l.keys[:0]
+	for _, key := range l.keys {
+		if !handle(key) {
+			kept = append(kept, key)
+		}
+	}
+	l.keys = kept
+}
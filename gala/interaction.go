@@ -0,0 +1,139 @@
+package gala
+
+import "slices"
+
+// appends the id of the box and of all its parents
+func appendIdChain(ids []ID, box *Box) []ID {
+	for ; box != nil; box = box.parent {
+		ids = append(ids, box.hash)
+	}
+	return ids
+}
+
+// CapturePointer makes the box keep receiving pointer events once the left
+// mouse button is pressed on it, until it's released. While captured, only the
+// box and its parents are hovered, even when the mouse leaves it.
+func (b *Box) CapturePointer() *Box {
+	b.capture = true
+	return b
+}
+
+// PassThrough makes the pointer ignore the box and everything in it, like
+// tooltips. The boxes under it are hovered and clicked instead.
+func (b *Box) PassThrough() *Box {
+	b.passThrough = true
+	return b
+}
+
+// trackPointer updates the hovered, pressed, clicked and captured boxes.
+// A box counts as hovered when the top most box under the mouse is it,
+// or one of its children.
+func (l *Layout) trackPointer() {
+	l.hoveredIds = appendIdChain(l.hoveredIds[:0], l.hoveredBox)
+	l.wheelId = 0
+	for p := l.hoveredBox; p != nil; p = p.parent {
+		if p.scrollable {
+			l.wheelId = p.hash
+			break
+		}
+	}
+	l.clickedIds = l.clickedIds[:0]
+	l.releasedId = 0
+
+	if l.capturedId != 0 {
+		if l.MouseDown(MouseLeft) {
+			l.hoveredIds = append(l.hoveredIds[:0], l.capturedIds...)
+		} else {
+			l.releasedId, l.capturedId = l.capturedId, 0
+		}
+	}
+	if l.MousePressed(MouseLeft) {
+		l.pressedIds = append(l.pressedIds[:0], l.hoveredIds...)
+		for box := l.hoveredBox; box != nil; box = box.parent {
+			if box.capture {
+				l.capturedId = box.hash
+				l.capturedIds = appendIdChain(l.capturedIds[:0], box)
+				break
+			}
+		}
+	} else if !l.MouseDown(MouseLeft) && len(l.pressedIds) > 0 {
+		// released, it's a click for the boxes that are still under the mouse
+		for _, id := range l.pressedIds {
+			if slices.Contains(l.hoveredIds, id) {
+				l.clickedIds = append(l.clickedIds, id)
+			}
+		}
+		l.pressedIds = l.pressedIds[:0]
+	}
+}
+
+// Scrollable makes the box take the mouse wheel, see ConsumeWheel.
+// The boxes around it don't scroll while the mouse is over it.
+func (b *Box) Scrollable() *Box {
+	b.scrollable = true
+	return b
+}
+
+// ConsumeWheel returns how far the mouse wheel moved this frame when the box
+// with the id was the nearest Scrollable box under the mouse during the last
+// End, and keeps the boxes around it from scrolling too. It's 0 otherwise.
+func (l *Layout) ConsumeWheel(id ID) float32 {
+	if id == 0 || id != l.wheelId {
+		return 0
+	}
+	wheel := l.wheel
+	l.wheel = 0
+	return wheel
+}
+
+// Hovered reports whether the mouse was over the box with the id during the last End.
+func (l *Layout) Hovered(id ID) bool {
+	return id != 0 && slices.Contains(l.hoveredIds, id)
+}
+
+// Pressed reports whether the left mouse button was pressed on the box
+// with the id and is still held down.
+func (l *Layout) Pressed(id ID) bool {
+	return id != 0 && slices.Contains(l.pressedIds, id)
+}
+
+// Clicked reports whether the box with the id was clicked during the last End,
+// meaning the left mouse button was pressed and released over it.
+func (l *Layout) Clicked(id ID) bool {
+	return id != 0 && slices.Contains(l.clickedIds, id)
+}
+
+// Captured reports whether the box with the id has captured the pointer,
+// meaning it's being dragged.
+func (l *Layout) Captured(id ID) bool {
+	return id != 0 && l.capturedId == id
+}
+
+// Released reports whether the box with the id lost the pointer capture
+// during the last End, because the mouse button was released.
+func (l *Layout) Released(id ID) bool {
+	return id != 0 && l.releasedId == id
+}
+
+// MouseDelta returns how far the mouse moved since the last End.
+func (l *Layout) MouseDelta() (dx, dy int32) {
+	x, y := l.MousePos()
+	return x - l.lastMouseX, y - l.lastMouseY
+}
+
+// remember where every box ended up, for widgets that need it before the next End
+func (l *Layout) storeRects(list []*Box) {
+	if l.rects == nil {
+		l.rects = make(map[ID]Rect)
+	}
+	clear(l.rects)
+	for _, p := range list {
+		l.rects[p.hash] = p.Rect()
+	}
+}
+
+// Rect returns the rect the box with the id had at the last End.
+func (l *Layout) Rect(id ID) (Rect, bool) {
+	r, ok := l.rects[id]
+	return r, ok
+}
@@ -0,0 +1,85 @@
+package gala
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConsumeWheel(t *testing.T) {
+	l, r := newTestLayout()
+	input := &testInput{}
+	l.UseInput(input)
+	r.mouseX, r.mouseY = 20, 20
+
+	var outer, inner, plain *Box
+	frame := func() {
+		inner = l.Box().Id("inner").Scrollable().Size(50, 50)
+		plain = l.Box().Id("plain").Size(50, 50)
+		outer = l.Box().Id("outer").Scrollable().Size(200, 200).Contains(inner, plain)
+	}
+	frame()
+	l.End(r)
+
+	input.wheel = 1
+	frame()
+	if wheel := l.ConsumeWheel(outer.ID()); wheel != 0 {
+		t.Errorf("the outer box got %v, the inner one is under the mouse", wheel)
+	}
+	if wheel := l.ConsumeWheel(plain.ID()); wheel != 0 {
+		t.Errorf("a box that isn't scrollable got %v", wheel)
+	}
+	if wheel := l.ConsumeWheel(inner.ID()); wheel != 1 {
+		t.Errorf("the inner box got %v, want 1", wheel)
+	}
+	if wheel := l.ConsumeWheel(inner.ID()); wheel != 0 {
+		t.Errorf("the wheel was consumed twice, got %v", wheel)
+	}
+	l.End(r)
+
+	// outside the inner box the outer one scrolls
+	r.mouseX, r.mouseY = 150, 150
+	frame()
+	l.End(r)
+	frame()
+	if wheel := l.ConsumeWheel(outer.ID()); wheel != 1 {
+		t.Errorf("the outer box got %v, want 1", wheel)
+	}
+	l.End(r)
+}
+
+func TestWheelScrollsListsAndTextAreas(t *testing.T) {
+	l, r := newTestLayout()
+	input := &testInput{}
+	l.UseInput(input)
+	r.mouseX, r.mouseY = 20, 20
+	text := strings.Repeat("line\n", 50)
+
+	var outer, list, area *Box
+	frame := func() {
+		list = l.VirtualList("list", 100, 20, func(i int) *Box { return l.Box() })
+		area = l.TextArea("area", &text)
+		outer = l.Box().Id("outer").Scrollable().Size(800, 600).Contains(list, area)
+	}
+	scrolled := func() (listScrolled, areaScrolled bool) {
+		return State[virtualListState](l, list.ID()).scroll > 0, State[textAreaState](l, area.ID()).scroll > 0
+	}
+	frame()
+	l.End(r)
+
+	input.wheel = -1
+	frame()
+	if wheel := l.ConsumeWheel(outer.ID()); wheel != 0 {
+		t.Errorf("the outer box got %v, the list is under the mouse", wheel)
+	}
+	l.End(r)
+	if list, area := scrolled(); !list || area {
+		t.Errorf("list scrolled %v and text area %v, want only the list", list, area)
+	}
+
+	r.mouseX, r.mouseY = 320, 20
+	frame()
+	l.End(r)
+	if _, area := scrolled(); !area {
+		t.Error("the text area didn't scroll with the mouse over it")
+	}
+}
@@ -3,12 +3,13 @@ package gala
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"log"
 	"reflect"
 )
 
-type layout struct {
+type Layout struct {
 	boxes   []Box
 	rootBox Box
 
@@ -17,14 +18,72 @@ type layout struct {
 	thirdQueue  []*Box
 
 	count int32
+
+	// debug mode reports duplicate sibling ids
+	debug       bool
+	seenIds     map[ID]struct{}
+	reportedIds map[ID]uint64 // the last frame each was seen in
+
+	renderer     Renderer // the one passed to the last End
+	input        Input
+	clipboard    Clipboard
+	frameStarted bool
+	keys         []Key  // pressed this frame
+	chars        []rune // typed this frame
+	wheel        float32
+	hoveredBox   *Box // the top most box under the mouse
+
+	// pointer state of the last End, see trackPointer
+	hoveredIds []ID
+	pressedIds []ID
+	clickedIds []ID
+	wheelId    ID // the nearest scrollable box under the mouse
+	// the box that keeps getting pointer events while the button is held
+	capturedId             ID
+	capturedIds            []ID // the captured box and its parents
+	releasedId             ID
+	lastMouseX, lastMouseY int32
+
+	rects   map[ID]Rect   // of the last End
+	lastIds map[string]ID // by the Id of the boxes of the last End, 0 when several had it
+
+	// frame clock
+	frame        uint64
+	now          time.Time
+	clock        func() time.Time
+	hoverSince   map[ID]time.Time
+	tooltipDelay time.Duration
+
+	states map[stateKey]*stateEntry
+
+	focusedId       ID
+	pendingFocus    ID
+	hasPendingFocus bool
+	focusable       []*Box // in tree order
+	tabOrder        []*Box
+	modal           *Box // the active one
+
+	variants []styleVariant // declared this frame
+	tokens   []tokenRef
+	theme    Theme
+
+	stylesheet *Stylesheet
+	watchers   []Polled
+	lastWatch  time.Time
+	lastRoots  []*Box // the children of the root at the last End, for snapshots
 }
 
 // NewLayout initializes the layout and prints memory usage
-func NewLayout(screenWidth, screenHeight int32, boxPoolSize uint16) layout {
-	l := layout{boxes: make([]Box, boxPoolSize)}
+func NewLayout(screenWidth, screenHeight int32, boxPoolSize uint16) Layout {
+	l := Layout{
+		boxes:        make([]Box, boxPoolSize),
+		tooltipDelay: defaultTooltipDelay,
+		theme:        DarkTheme(),
+	}
 	l.rootBox.
 		Size(float32(screenWidth), float32(screenHeight)).
 		Id("Root")
+	l.rootBox.hash = hashString(0, l.rootBox.id)
 
 	// Print memory usage of the slice of Box structs
 	size := reflect.TypeOf(l.rootBox).Size() * uintptr(boxPoolSize)
@@ -32,16 +91,30 @@ func NewLayout(screenWidth, screenHeight int32, boxPoolSize uint16) layout {
 	return l
 }
 
+// DebugMode enables extra checks that are too slow for release builds,
+// like reporting siblings that share the same Id.
+func (l *Layout) DebugMode(enabled bool) {
+	l.debug = enabled
+	if enabled && l.seenIds == nil {
+		l.seenIds = make(map[ID]struct{})
+		l.reportedIds = make(map[ID]uint64)
+	}
+}
+
 // Box retrieves a free box from the pool
-func (l *layout) Box() *Box {
+func (l *Layout) Box() *Box {
 	if int(l.count) >= len(l.boxes) {
 		log.Panic("Ran out of available boxes. Did you initialize enough?")
 	}
 
+	if l.count == 0 {
+		l.beginFrame()
+	}
 	box := &l.boxes[l.count]
 	l.count++
 	box.reset()
 	box.inUse = true
+	box.layout = l
 	return box
 }
 
@@ -59,17 +132,39 @@ func printBoxHierarchy(b *Box, indent string) {
 	}
 }
 
-func (l *layout) End(renderer Renderer) {
+func (l *Layout) End(renderer Renderer) {
 	defer l.rootBoxRefresh()
+	l.renderer = renderer
+	l.beginFrame()
 	l.calculate()
 	root := &l.rootBox
 	queue := []*Box{}
 	queue = append(queue, root)
 	list := []*Box{}
+	l.modal = nil
 	for len(queue) > 0 {
 		node := DequeueFront(&queue)
+		node.resolveModal()
+		if node.modal == node {
+			node.layer = modalLayer
+			if l.modal != nil {
+				node.layer = l.modal.layer + modalLayerStep
+			}
+			l.modal = node
+		} else if node.anchor != nil {
+			node.layer = node.anchor.layer + 1
+		} else if node.parent != nil {
+			node.layer = node.parent.layer
+		}
+		if node.parent != nil && node.parent.passThrough {
+			node.passThrough = true
+		}
+		node.resolveClip(root.Rect())
 
 		list = append(list, node)
+		if node.focusable && !node.disabled {
+			l.focusable = append(l.focusable, node)
+		}
 		for i := len(node.children) - 1; i >= 0; i-- {
 			p := node.children[i]
 			if p.display == displayNone {
@@ -78,40 +173,88 @@ func (l *layout) End(renderer Renderer) {
 			queue = append(queue, p)
 		}
 	}
-	sort.Slice(list, func(i, j int) bool {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].layer != list[j].layer {
+			return list[i].layer < list[j].layer
+		}
 		return list[i].zindex < list[j].zindex
 	})
+	mouseX, mouseY := renderer.MousePos()
+	l.hoveredBox = boxAt(list, mouseX, mouseY)
+	l.trackPointer()
+	l.trackHoverTime()
+	l.lastMouseX, l.lastMouseY = mouseX, mouseY
+	l.storeRects(list)
+	for _, p := range list {
+		if p.update != nil {
+			p.update(p)
+		}
+	}
+	l.trapFocus()
+	l.handlePointerFocus(list)
+	l.handleFocus()
+	l.closeModal()
 
+	clip := root.clip
+	renderer.BeginScissor(int32(clip.X), int32(clip.Y), int32(clip.Width), int32(clip.Height))
 	for _, p := range list {
-		if p.onHover != nil {
-			mouseX, mouseY := renderer.MousePos()
-			if p.pointIsInside(mouseX, mouseY) {
-				p.onHover(p)
-			}
+		if p.onHover != nil && l.Hovered(p.hash) {
+			p.onHover(p)
 		}
-		renderer.DrawRect(int32(p.x), int32(p.y), int32(p.width), int32(p.height), p.backgroundColor)
+		if p.clip != clip {
+			clip = p.clip
+			renderer.EndScissor()
+			renderer.BeginScissor(int32(clip.X), int32(clip.Y), int32(clip.Width), int32(clip.Height))
+		}
+		p.draw(renderer)
 	}
+	renderer.EndScissor()
+	l.drawWatchErrors(renderer)
+	l.lastRoots = append(l.lastRoots[:0], root.children...)
+	l.pruneStates()
 
+	if l.lastIds == nil {
+		l.lastIds = make(map[string]ID)
+	}
+	clear(l.lastIds)
 	for i := range l.boxes {
 		box := &l.boxes[i]
+		// remember the id so the slot can be identified before the next End
+		box.prevHash, box.prevId = 0, ""
+		if box.inUse {
+			box.prevHash, box.prevId = box.hash, box.id
+		}
+		if box.inUse && box.id != "" {
+			// and by its Id, in case the slot changes
+			if _, taken := l.lastIds[box.id]; taken {
+				l.lastIds[box.id] = 0
+			} else {
+				l.lastIds[box.id] = box.hash
+			}
+		}
 		box.inUse = false
 	}
 
 }
 
-func (l *layout) calculate() {
-
-	// pass 0: add boxes to root box
-	for i := range l.boxes {
-		if i > int(l.count) {
-			break
-		}
-		box := &l.boxes[i]
-		// add boxes to root box if they dont have a parent.
-		if box.parent == nil && box.inUse {
-			l.rootBox.Contains(box)
+func (l *Layout) calculate() {
+
+	// pass 0: add boxes to root box if they dont have a parent.
+	// Overlays always end up here, Contains skips them, so their parent
+	// is set here rather than with Contains. They go after the other
+	// boxes, so opening a menu or a tooltip doesn't change the index,
+	// and with it the ID, of the boxes without an Id declared after it.
+	for _, overlays := range [...]bool{false, true} {
+		for i := range l.boxes {
+			if i > int(l.count) {
+				break
+			}
+			box := &l.boxes[i]
+			if box.parent == nil && box.inUse && (box.anchor != nil) == overlays {
+				box.parent = &l.rootBox
+				l.rootBox.children = append(l.rootBox.children, box)
+			}
 		}
-
 	}
 
 	//	 in a queue, you can only add a new item to the back and remove items from the front
@@ -120,6 +263,7 @@ func (l *layout) calculate() {
 	// firstQueue is only used to store the currenltly visited boxes
 	for len(l.firstQueue) > 0 { // Keep looping until the firstQueue is empty, meaning we've processed all boxes.
 		element := Dequeue(&l.firstQueue)
+		l.resolveChildIds(element)
 
 		for _, p := range element.children {
 			l.firstQueue = append(l.firstQueue, p)
@@ -127,14 +271,29 @@ func (l *layout) calculate() {
 		}
 
 	} // end of first pass
+	l.resolveTokens()
+	l.applyStylesheet()
+	l.applyVariants()
+	// sizing overwrites the style, keep it for snapshots
+	l.rootBox.declared = l.rootBox.baseStyle
+	for _, box := range l.secondQueue {
+		box.declared = box.baseStyle
+		box.insetBorder()
+	}
 	l.secondPass()
 	l.thirdPass()
+	l.placeOverlays()
 	// printBoxHierarchy(&l.rootBox, "")
 }
 
-func (l *layout) rootBoxRefresh() {
+func (l *Layout) rootBoxRefresh() {
 	l.rootBox.children = l.rootBox.children[:0]
 	l.count = 0
+	l.frameStarted = false
+	l.hoveredBox = nil
+	l.focusable = l.focusable[:0]
+	l.variants = l.variants[:0]
+	l.tokens = l.tokens[:0]
 
 	l.firstQueue = l.firstQueue[:0]
 	l.secondQueue = l.secondQueue[:0]
@@ -142,10 +301,11 @@ func (l *layout) rootBoxRefresh() {
 }
 
 // Second pass: resolve wrapping children, going bottom-up, level-order.
-func (l *layout) secondPass() {
+func (l *Layout) secondPass() {
 	for len(l.secondQueue) > 0 {
 		element := DequeueFront(&l.secondQueue)
 		l.thirdQueue = append(l.thirdQueue, element)
+		l.fitText(element)
 
 		if element.width == 0 {
 			var childrenCount int16
@@ -165,7 +325,7 @@ func (l *layout) secondPass() {
 				}
 
 			} // end of loop
-			element.width += float32(element.margin.left) +
+			element.width += float32(element.padding.left) +
 				float32(element.padding.right)
 			if element.flexDirection == directionRow {
 				element.width += float32((childrenCount - 1) * element.gap)
@@ -200,7 +360,7 @@ func (l *layout) secondPass() {
 
 // Third tree pass: resolve flex.
 // Going top-down, level order.
-func (l *layout) thirdPass() {
+func (l *Layout) thirdPass() {
 	for len(l.thirdQueue) > 0 {
 		element := DequeueFront(&l.thirdQueue)
 
@@ -220,14 +380,14 @@ func (l *layout) thirdPass() {
 
 		if element.left != 0 && element.right != 0 && element.width == 0 {
 			element.x = parent.x + element.left
-			element.width = parent.width - float32(element.left-element.right)
-		} else if element.left == 0 {
+			element.width = parent.width - float32(element.left+element.right)
+		} else if element.left != 0 {
 			if element.position == positionAbsolute {
 				element.x = parent.x + element.left
 			} else {
 				element.x += element.left
 			}
-		} else if element.right == 0 {
+		} else if element.right != 0 {
 			if element.position == positionAbsolute {
 				element.x =
 					parent.x +
@@ -236,23 +396,23 @@ func (l *layout) thirdPass() {
 						int16(element.width)
 
 			} else {
-				element.x = parent.x - element.right
+				element.x -= element.right
 			}
 		} else if element.position == positionAbsolute {
 			// If position is "absolute" but offsets are not specified, set
 			// position to parent's top left corner.
 			element.x = parent.x
 		}
-		if element.top == 0 && element.bottom == 0 && element.height == 0 {
-			element.x = parent.x + element.top
-			element.height = parent.height - float32(element.top-element.bottom)
-		} else if element.top == 0 {
+		if element.top != 0 && element.bottom != 0 && element.height == 0 {
+			element.y = parent.y + element.top
+			element.height = parent.height - float32(element.top+element.bottom)
+		} else if element.top != 0 {
 			if element.position == positionAbsolute {
 				element.y = parent.y + element.top
 			} else {
 				element.y += element.top
 			}
-		} else if element.bottom == 0 {
+		} else if element.bottom != 0 {
 			if element.position == positionAbsolute {
 				element.y =
 					parent.y +
@@ -260,53 +420,13 @@ func (l *layout) thirdPass() {
 						element.bottom -
 						int16(element.height)
 			} else {
-				element.y = parent.y - element.bottom
+				element.y -= element.bottom
 			}
 		} else if element.position == positionAbsolute {
 			// If position is "absolute" but offsets are not specified, set
 			// position to parent's top left corner.
 			element.y = parent.y
 		}
-		//apply align self
-		if element.position == positionAbsolute && parent != nil {
-			//alignSelf is a property that allows overriding parent's alignItems for the current element.
-			// apply to row
-			if parent.flexDirection == directionRow {
-				switch element.alignBits {
-				case selfAlignCenter:
-					element.y = element.y +
-						int16(element.height)/2 -
-						int16(element.height)/2
-				case selfAlignFlexEnd:
-					element.y = element.y +
-						int16(parent.height) -
-						int16(element.height) -
-						parent.bottom -
-						parent.padding.top
-				case selfAlignStretch:
-					element.height = element.height -
-						parent.height -
-						float32(parent.padding.bottom) -
-						float32(parent.padding.top)
-				}
-			}
-			// also to column
-			if parent.flexDirection == directionColumn {
-				switch element.alignBits {
-				case selfAlignCenter:
-					element.x = element.x +
-						int16(element.width/2) - int16(element.width/2)
-				case selfAlignFlexEnd:
-					element.x = element.x +
-						int16(element.width/2) - int16(element.width/2)
-				case selfAlignStretch:
-					element.width = parent.width -
-						float32(parent.padding.left) -
-						float32(parent.padding.right)
-				}
-			}
-
-		} // end of align self
 		// Set sizes for children that use percentages.
 		for _, p := range element.children {
 			// if its a percentage (between 0 and -1)
@@ -386,7 +506,7 @@ func (l *layout) thirdPass() {
 					element.justifyContent != justifySpaceBetween &&
 					element.justifyContent != justifySpaceAround &&
 					element.justifyContent != justifySpaceEvenly {
-					p.height = float32((p.flex / totalFlex) * int16(availableHeight))
+					p.height = float32(p.flex) / float32(totalFlex) * availableHeight
 				}
 			}
 		} // end of  loop
@@ -472,37 +592,41 @@ func (l *layout) thirdPass() {
 			}
 		}
 
+		// align items, or the child's align self, across the direction.
+		// The margins are added when the child itself is placed.
 		for _, p := range element.children {
-			if p.position == positionAbsolute {
+			if p.position == positionAbsolute ||
+				p.display == displayNone {
 				continue
 			}
 
 			switch element.flexDirection {
 			case directionRow:
-				if element.alignBits.has(itemsAlignCenter) {
-					p.y = element.y + int16(element.height/2)
-				}
-				if element.alignBits.has(itemsAlignFlexEnd) {
-					p.y = element.y + int16(element.height/2)
-				}
-				if element.alignBits.has(itemsAlignStretch) &&
-					p.height == 0 {
-					p.height = element.height - float32(element.padding.top-element.padding.bottom)
+				content := element.height - float32(element.padding.top+element.padding.bottom)
+				space := content - p.height - float32(p.margin.top+p.margin.bottom)
+				switch crossAlign(element, p) {
+				case alignCenter:
+					p.y = element.y + element.padding.top + int16(space/2)
+				case alignFlexEnd:
+					p.y = element.y + element.padding.top + int16(space)
+				case alignStretch:
+					if p.height == 0 {
+						p.height = space
+					}
 				}
 
 			case directionColumn:
-				if element.alignBits.has(itemsAlignCenter) {
-					p.x = element.x + int16(element.width)/2
-				}
-				if element.alignBits.has(itemsAlignFlexEnd) {
-					p.x = element.x +
-						int16(element.width) -
-						int16(p.width) -
-						element.padding.right
-				}
-				if element.alignBits.has(itemsAlignStretch) &&
-					p.width == 0 {
-					p.width = element.width - float32(element.padding.left-element.padding.right)
+				content := element.width - float32(element.padding.left+element.padding.right)
+				space := content - p.width - float32(p.margin.left+p.margin.right)
+				switch crossAlign(element, p) {
+				case alignCenter:
+					p.x = element.x + element.padding.left + int16(space/2)
+				case alignFlexEnd:
+					p.x = element.x + element.padding.left + int16(space)
+				case alignStretch:
+					if p.width == 0 {
+						p.width = space
+					}
 				}
 			}
 		}
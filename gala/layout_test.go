@@ -0,0 +1,219 @@
+package gala
+
+import "testing"
+
+func TestContentSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent func(l *Layout, children ...*Box) *Box
+		want   Rect
+	}{
+		{"row", func(l *Layout, children ...*Box) *Box {
+			return l.Box().Contains(children...)
+		}, Rect{0, 0, 50, 10}},
+		{"column", func(l *Layout, children ...*Box) *Box {
+			return l.Box().FlexDirection_Column().Contains(children...)
+		}, Rect{0, 0, 30, 20}},
+		{"padding is added", func(l *Layout, children ...*Box) *Box {
+			return l.Box().PaddingLeft(3).PaddingRight(4).PaddingTop(5).PaddingBottom(6).Contains(children...)
+		}, Rect{0, 0, 57, 21}},
+		{"its own margin isn't", func(l *Layout, children ...*Box) *Box {
+			return l.Box().MarginLeft(7).MarginTop(7).Contains(children...)
+		}, Rect{7, 7, 50, 10}},
+		{"margins of the children are", func(l *Layout, children ...*Box) *Box {
+			children[0].MarginLeft(2).MarginBottom(3)
+			return l.Box().Contains(children...)
+		}, Rect{0, 0, 52, 13}},
+	}
+	for _, test := range tests {
+		l, r := newTestLayout()
+		parent := test.parent(l, l.Box().Size(20, 10), l.Box().Size(30, 10))
+		l.End(r)
+		if got := parent.Rect(); got != test.want {
+			t.Errorf("%s: rect %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFlex(t *testing.T) {
+	tests := []struct {
+		name   string
+		column bool
+		flex   [3]int16
+		want   [3]float32 // sizes along the direction
+	}{
+		{"row", false, [3]int16{1, 2, 3}, [3]float32{50, 100, 150}},
+		{"column", true, [3]int16{1, 2, 3}, [3]float32{50, 100, 150}},
+		{"column of thirds", true, [3]int16{1, 1, 1}, [3]float32{100, 100, 100}},
+		{"column with a fixed child", true, [3]int16{0, 1, 1}, [3]float32{60, 120, 120}},
+	}
+	for _, test := range tests {
+		l, r := newTestLayout()
+		parent := l.Box().Size(300, 300)
+		if test.column {
+			parent.FlexDirection_Column()
+		}
+		var children [3]*Box
+		for i, flex := range test.flex {
+			children[i] = l.Box().Flex(flex).Size(60, 60)
+			if flex != 0 {
+				children[i].Size(0, 0)
+			}
+			parent.Contains(children[i])
+		}
+		l.End(r)
+		for i, child := range children {
+			got := child.width
+			if test.column {
+				got = child.height
+			}
+			if got != test.want[i] {
+				t.Errorf("%s: child %d is %v long, want %v", test.name, i, got, test.want[i])
+			}
+		}
+	}
+}
+
+func TestPercentSizes(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height float32
+		want          Rect
+	}{
+		{"half and full", Percent(50), Percent(100), Rect{0, 0, 100, 100}},
+		{"quarter", Percent(25), Percent(25), Rect{0, 0, 50, 25}},
+		{"over 100 is clamped", Percent(150), Percent(300), Rect{0, 0, 200, 100}},
+		{"below -1 is 100%", -5, -2, Rect{0, 0, 200, 100}},
+		{"pixels", 30, 40, Rect{0, 0, 30, 40}},
+	}
+	// Size has to clamp like Width and Height
+	setters := map[string]func(b *Box, w, h float32){
+		"Width and Height": func(b *Box, w, h float32) { b.Width(w).Height(h) },
+		"Size":             func(b *Box, w, h float32) { b.Size(w, h) },
+	}
+	for _, test := range tests {
+		for setter, set := range setters {
+			l, r := newTestLayout()
+			child := l.Box()
+			set(child, test.width, test.height)
+			l.Box().Size(200, 100).Contains(child)
+			l.End(r)
+			if got := child.Rect(); got != test.want {
+				t.Errorf("%s with %s: rect %v, want %v", test.name, setter, got, test.want)
+			}
+		}
+	}
+}
+
+func TestOffsets(t *testing.T) {
+	tests := []struct {
+		name  string
+		child func(l *Layout) *Box
+		want  Rect
+	}{
+		{"absolute without offsets", func(l *Layout) *Box {
+			return l.Box().Position_Absolute().Size(20, 10)
+		}, Rect{100, 50, 20, 10}},
+		{"absolute left and top", func(l *Layout) *Box {
+			return l.Box().Position_Absolute().Left(5).Top(7).Size(20, 10)
+		}, Rect{105, 57, 20, 10}},
+		{"absolute right and bottom", func(l *Layout) *Box {
+			return l.Box().Position_Absolute().Right(5).Bottom(7).Size(20, 10)
+		}, Rect{275, 133, 20, 10}},
+		{"absolute left and right stretch", func(l *Layout) *Box {
+			return l.Box().Position_Absolute().Left(5).Right(15).Height(10)
+		}, Rect{105, 50, 180, 10}},
+		{"absolute top and bottom stretch", func(l *Layout) *Box {
+			return l.Box().Position_Absolute().Top(5).Bottom(15).Width(10)
+		}, Rect{100, 55, 10, 80}},
+		{"relative left and top", func(l *Layout) *Box {
+			return l.Box().Left(5).Top(7).Size(20, 10)
+		}, Rect{130, 57, 20, 10}},
+		{"relative right and bottom", func(l *Layout) *Box {
+			return l.Box().Right(5).Bottom(7).Size(20, 10)
+		}, Rect{120, 43, 20, 10}},
+		{"empty box keeps its place", func(l *Layout) *Box {
+			// not stretched across the row
+			return l.Box().Width(20).AlignSelf_FlexStart()
+		}, Rect{125, 50, 20, 0}},
+	}
+	for _, test := range tests {
+		l, r := newTestLayout()
+		child := test.child(l)
+		// the child comes after a 25 pixel wide box in a row
+		l.Box().Position_Absolute().Left(100).Top(50).Size(200, 100).Contains(
+			l.Box().Size(25, 25),
+			child,
+		)
+		l.End(r)
+		if got := child.Rect(); got != test.want {
+			t.Errorf("%s: rect %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent func(l *Layout) *Box
+		child  func(l *Layout) *Box
+		want   Rect
+	}{
+		{"centered in a row", func(l *Layout) *Box {
+			return l.Box().AlignItems_Center()
+		}, func(l *Layout) *Box {
+			return l.Box().Size(20, 10)
+		}, Rect{110, 95, 20, 10}},
+		{"centered in a column", func(l *Layout) *Box {
+			return l.Box().FlexDirection_Column().AlignItems_Center()
+		}, func(l *Layout) *Box {
+			return l.Box().Size(20, 10)
+		}, Rect{190, 60, 20, 10}},
+		{"centered with margins", func(l *Layout) *Box {
+			return l.Box().AlignItems_Center()
+		}, func(l *Layout) *Box {
+			return l.Box().Size(20, 10).MarginTop(20)
+		}, Rect{110, 105, 20, 10}},
+		{"at the end of a row", func(l *Layout) *Box {
+			return l.Box().AlignItems_FlexEnd()
+		}, func(l *Layout) *Box {
+			return l.Box().Size(20, 10)
+		}, Rect{110, 130, 20, 10}},
+		{"at the start of a row", func(l *Layout) *Box {
+			return l.Box().AlignItems_FlexStart()
+		}, func(l *Layout) *Box {
+			return l.Box().Size(20, 10)
+		}, Rect{110, 60, 20, 10}},
+		{"stretched across a row", func(l *Layout) *Box {
+			return l.Box()
+		}, func(l *Layout) *Box {
+			return l.Box().Width(20)
+		}, Rect{110, 60, 20, 80}},
+		{"align self over align items", func(l *Layout) *Box {
+			return l.Box().AlignItems_FlexStart()
+		}, func(l *Layout) *Box {
+			return l.Box().Size(20, 10).AlignSelf_Center()
+		}, Rect{110, 95, 20, 10}},
+		{"the last setter wins", func(l *Layout) *Box {
+			return l.Box().AlignItems_FlexEnd().AlignItems_Center()
+		}, func(l *Layout) *Box {
+			return l.Box().Size(20, 10)
+		}, Rect{110, 95, 20, 10}},
+	}
+	for _, test := range tests {
+		l, r := newTestLayout()
+		child := test.child(l)
+		// 180 by 80 pixels inside the padding
+		test.parent(l).
+			Position_Absolute().
+			Left(100).
+			Top(50).
+			Size(200, 100).
+			Padding(10).
+			Contains(child)
+		l.End(r)
+		if got := child.Rect(); got != test.want {
+			t.Errorf("%s: rect %v, want %v", test.name, got, test.want)
+		}
+	}
+}
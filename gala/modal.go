@@ -0,0 +1,102 @@
+package gala
+
+import "image/color"
+
+// modals are painted above the layers of everything else,
+// each one far enough above the previous for the overlays in it
+const (
+	modalLayer     = 32
+	modalLayerStep = 16
+)
+
+var backdropColor = color.RGBA{0, 0, 0, 128}
+
+/*
+Modal returns a backdrop covering the whole root, for a dialog to be put in.
+The boxes in it are centered.
+
+While it's shown, nothing beneath it gets the pointer, Tab and the arrow keys
+only move the focus between the boxes in it, and the first of them is focused
+when nothing in it is. onClose is called at End when Escape is pressed or the
+backdrop itself is clicked, it may be nil. Only the modal declared last is
+active when several are shown. Don't put a modal inside other boxes.
+
+	if open {
+		layout.Modal("confirm", func() { open = false }).Contains(dialog)
+	}
+*/
+func (l *Layout) Modal(id string, onClose func()) *Box {
+	box := l.Box().
+		Id(id).
+		Position_Absolute().
+		Size(l.rootBox.width, l.rootBox.height).
+		BackgroundColor(backdropColor)
+	box.modal = box
+	box.onClose = onClose
+	return box
+}
+
+// the modal the box belongs to, resolved when the tree is walked
+func (b *Box) resolveModal() {
+	switch {
+	case b.modal == b:
+	case b.anchor != nil:
+		b.modal = b.anchor.modal
+	case b.parent != nil:
+		b.modal = b.parent.modal
+	}
+}
+
+// whether the box is the modal or inside it
+func (b *Box) inModal(modal *Box) bool {
+	return modal == nil || b.modal == modal
+}
+
+// keeps the focus inside the active modal
+func (l *Layout) trapFocus() {
+	if l.modal == nil {
+		return
+	}
+	focusable := l.focusable[:0]
+	for _, p := range l.focusable {
+		if p.inModal(l.modal) {
+			focusable = append(focusable, p)
+		}
+	}
+	l.focusable = focusable
+}
+
+// closes the active modal on Escape or a click on its backdrop
+func (l *Layout) closeModal() {
+	m := l.modal
+	if m == nil || m.onClose == nil {
+		return
+	}
+	clicked := l.MousePressed(MouseLeft) && l.hoveredBox == m
+	if l.KeyPressed(KeyEscape) {
+		l.ConsumeKey(KeyEscape)
+		clicked = true
+	}
+	if clicked {
+		m.onClose()
+	}
+}
+
+// centers the children of the box in it
+func (b *Box) centerChildren() {
+	if len(b.children) == 0 {
+		return
+	}
+	bounds := b.children[0].Rect()
+	for _, p := range b.children[1:] {
+		r := p.Rect()
+		bounds.X, bounds.Y = min(bounds.X, r.X), min(bounds.Y, r.Y)
+		right, bottom := max(bounds.Right(), r.Right()), max(bounds.Bottom(), r.Bottom())
+		bounds.Width, bounds.Height = right-bounds.X, bottom-bounds.Y
+	}
+	dx := b.x + (int16(b.width)-bounds.Width)/2 - bounds.X
+	dy := b.y + (int16(b.height)-bounds.Height)/2 - bounds.Y
+	for _, p := range b.children {
+		p.translate(dx, dy)
+	}
+}
@@ -0,0 +1,58 @@
+package gala
+
+import "testing"
+
+func TestModal(t *testing.T) {
+	l, r := newTestLayout()
+	input := &testInput{}
+	l.UseInput(input)
+	closed := 0
+	var below, first, second *Box
+	frame := func() {
+		below = l.Box().Id("below").Focusable().Size(800, 600)
+		first = l.Box().Id("first").Focusable().Size(50, 20)
+		second = l.Box().Id("second").Focusable().Size(50, 20)
+		dialog := l.Box().Id("dialog").Size(200, 100).Contains(first, second)
+		l.Modal("modal", func() { closed++ }).Contains(dialog)
+		l.End(r)
+	}
+	r.mouseX, r.mouseY = 10, 10
+	frame()
+	frame()
+	if l.Hovered(below.ID()) {
+		t.Error("the box beneath the backdrop is hovered")
+	}
+	if !l.Focused(first.ID()) {
+		t.Error("the first box in the modal isn't focused")
+	}
+
+	for _, want := range [...]*Box{second, first, second} {
+		input.keys = []Key{KeyTab}
+		frame()
+		if !l.Focused(want.ID()) {
+			t.Errorf("Tab focused %d, want %d, the focus has to stay in the modal", l.focusedId, want.ID())
+		}
+	}
+
+	input.keys = []Key{KeyEscape}
+	frame()
+	if closed != 1 {
+		t.Errorf("closed %d times after Escape, want once", closed)
+	}
+
+	// the dialog is centered, the mouse is over the backdrop
+	input.mousePressed = true
+	frame()
+	input.mousePressed = false
+	if closed != 2 {
+		t.Errorf("closed %d times after clicking the backdrop, want twice", closed)
+	}
+	r.mouseX, r.mouseY = 400, 300
+	frame()
+	input.mousePressed = true
+	frame()
+	input.mousePressed = false
+	if closed != 2 {
+		t.Errorf("closed %d times after clicking the dialog, want it to stay open", closed)
+	}
+}
@@ -0,0 +1,151 @@
+package gala
+
+type navDirection int8
+
+const (
+	navUp navDirection = iota
+	navDown
+	navLeft
+	navRight
+	navNone navDirection = -1
+)
+
+// NavUp overrides which box gets focus when navigating up from this box.
+func (b *Box) NavUp(target *Box) *Box {
+	b.nav[navUp] = target
+	return b
+}
+
+// NavDown overrides which box gets focus when navigating down from this box.
+func (b *Box) NavDown(target *Box) *Box {
+	b.nav[navDown] = target
+	return b
+}
+
+// NavLeft overrides which box gets focus when navigating left from this box.
+func (b *Box) NavLeft(target *Box) *Box {
+	b.nav[navLeft] = target
+	return b
+}
+
+// NavRight overrides which box gets focus when navigating right from this box.
+func (b *Box) NavRight(target *Box) *Box {
+	b.nav[navRight] = target
+	return b
+}
+
+func keyNavDirection(key Key) navDirection {
+	switch key {
+	case KeyUp:
+		return navUp
+	case KeyDown:
+		return navDown
+	case KeyLeft:
+		return navLeft
+	case KeyRight:
+		return navRight
+	}
+	return navNone
+}
+
+// handleNavigation moves focus with the arrow keys and the gamepad dpad.
+func (l *Layout) handleNavigation() {
+	for _, key := range l.keys {
+		if dir := keyNavDirection(key); dir != navNone {
+			l.navigate(dir)
+		}
+	}
+	if l.input == nil {
+		return
+	}
+	buttons := [...]GamepadButton{
+		navUp:    GamepadDpadUp,
+		navDown:  GamepadDpadDown,
+		navLeft:  GamepadDpadLeft,
+		navRight: GamepadDpadRight,
+	}
+	for dir, button := range buttons {
+		if l.input.GamepadPressed(button) {
+			l.navigate(navDirection(dir))
+		}
+	}
+}
+
+// navigate moves focus to the nearest focusable box in the direction.
+func (l *Layout) navigate(dir navDirection) {
+	current := l.focusableBox(l.focusedId)
+	if current == nil {
+		// nothing to move from, start at the beginning of the Tab order.
+		if len(l.tabOrder) > 0 {
+			l.setFocus(l.tabOrder[0])
+		}
+		return
+	}
+	if target := current.nav[dir]; target != nil {
+		// only if it's visible and focusable this frame
+		if l.focusableBox(target.hash) == target {
+			l.setFocus(target)
+		}
+		return
+	}
+
+	from := current.Rect()
+	var best *Box
+	var bestScore int32
+	for _, p := range l.focusable {
+		if p == current || p.tabIndex < 0 {
+			continue
+		}
+		score, ok := navScore(from, p.Rect(), dir)
+		if ok && (best == nil || score < bestScore) {
+			best, bestScore = p, score
+		}
+	}
+	if best != nil {
+		l.setFocus(best)
+	}
+}
+
+/*
+navScore rates how good of a target rect "to" is when moving from "from".
+lower is better, ok is false if "to" isn't in that direction at all.
+
+the distance along the direction is added to twice the distance
+across it, so boxes that line up with the current one win.
+*/
+func navScore(from, to Rect, dir navDirection) (score int32, ok bool) {
+	fromX, fromY := from.center()
+	toX, toY := to.center()
+
+	var along, across int32
+	switch dir {
+	case navUp:
+		ok = toY < fromY
+		along = int32(from.Y) - int32(to.Bottom())
+		across = spanDistance(from.X, from.Right(), to.X, to.Right())
+	case navDown:
+		ok = toY > fromY
+		along = int32(to.Y) - int32(from.Bottom())
+		across = spanDistance(from.X, from.Right(), to.X, to.Right())
+	case navLeft:
+		ok = toX < fromX
+		along = int32(from.X) - int32(to.Right())
+		across = spanDistance(from.Y, from.Bottom(), to.Y, to.Bottom())
+	case navRight:
+		ok = toX > fromX
+		along = int32(to.X) - int32(from.Right())
+		across = spanDistance(from.Y, from.Bottom(), to.Y, to.Bottom())
+	}
+	return max(0, along) + 2*across, ok
+}
+
+// distance between two 1d spans, 0 if they overlap
+func spanDistance(aStart, aEnd, bStart, bEnd int16) int32 {
+	if bStart > aEnd {
+		return int32(bStart) - int32(aEnd)
+	}
+	if aStart > bEnd {
+		return int32(aStart) - int32(bEnd)
+	}
+	return 0
+}
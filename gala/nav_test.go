@@ -0,0 +1,39 @@
+package gala
+
+import "testing"
+
+func TestNavScore(t *testing.T) {
+	from := Rect{100, 100, 50, 20}
+	tests := []struct {
+		name  string
+		to    Rect
+		dir   navDirection
+		score int32
+		ok    bool
+	}{
+		{"right in line", Rect{170, 100, 50, 20}, navRight, 20, true},
+		{"right and below", Rect{170, 140, 50, 20}, navRight, 20 + 2*20, true},
+		{"left in line", Rect{20, 100, 50, 20}, navLeft, 30, true},
+		{"down overlapping across", Rect{120, 130, 50, 20}, navDown, 10, true},
+		{"up touching", Rect{100, 80, 50, 20}, navUp, 0, true},
+		{"right is not left", Rect{170, 100, 50, 20}, navLeft, 0, false},
+		{"below is not up", Rect{100, 130, 50, 20}, navUp, 0, false},
+		{"overlapping is clamped", Rect{140, 100, 50, 20}, navRight, 0, true},
+	}
+	for _, test := range tests {
+		score, ok := navScore(from, test.to, test.dir)
+		if ok != test.ok || ok && score != test.score {
+			t.Errorf("%s: navScore = %d, %v, want %d, %v", test.name, score, ok, test.score, test.ok)
+		}
+	}
+}
+
+func TestNavScorePrefersAligned(t *testing.T) {
+	from := Rect{0, 0, 50, 20}
+	// closer, but far off to the side
+	diagonal, _ := navScore(from, Rect{60, 60, 50, 20}, navRight)
+	aligned, _ := navScore(from, Rect{100, 0, 50, 20}, navRight)
+	if aligned >= diagonal {
+		t.Errorf("aligned scored %d, diagonal %d, want aligned to win", aligned, diagonal)
+	}
+}
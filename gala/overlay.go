@@ -0,0 +1,113 @@
+package gala
+
+// Side of a rect, used to place overlays next to their anchor.
+// Opposite sides only differ in the lowest bit.
+type Side int8
+
+const (
+	SideBottom Side = iota
+	SideTop
+	SideRight
+	SideLeft
+)
+
+/*
+Overlay turns the box into a popover for the anchor, like a dropdown or a menu.
+
+It's laid out on its own, then moved next to the side of the anchor's
+computed rect. When it would overflow the root it moves to another side
+that fits, and it's kept inside the root. It's painted above everything else
+and isn't clipped by the parents of the anchor.
+
+Overlays don't need to be a child of anything, Contains ignores them.
+Clicking inside one focuses the nearest focusable parent of its anchor.
+*/
+func (b *Box) Overlay(anchor *Box, side Side) *Box {
+	b.anchor = anchor
+	b.side = side
+	return b.Position_Absolute()
+}
+
+/*
+OverlayAt turns the box into an overlay at the point, like a context menu
+at the mouse. Its top left corner goes there, unless it would overflow
+the root, then it opens upwards or to the left.
+
+It belongs to the root, clicking inside it focuses nothing.
+*/
+func (b *Box) OverlayAt(x, y int16) *Box {
+	b.atPoint, b.point = true, Rect{x, y, 0, 0}
+	return b.Overlay(&b.layout.rootBox, SideBottom)
+}
+
+// the side across from this one
+func (s Side) opposite() Side {
+	return s ^ 1
+}
+
+// the rect next to the side of the anchor, and whether it fits the bounds
+// along that side. Across it, it's only moved to stay inside the bounds.
+func sideRect(anchor Rect, width, height int16, side Side, bounds Rect) (Rect, bool) {
+	r := Rect{anchor.X, anchor.Y, width, height}
+	switch side {
+	case SideBottom:
+		r.Y = anchor.Bottom()
+	case SideTop:
+		r.Y = anchor.Y - height
+	case SideRight:
+		r.X = anchor.Right()
+	case SideLeft:
+		r.X = anchor.X - width
+	}
+	fits := r.Y >= bounds.Y && r.Bottom() <= bounds.Bottom()
+	if side == SideRight || side == SideLeft {
+		fits = r.X >= bounds.X && r.Right() <= bounds.Right()
+	}
+	r.X = max(bounds.X, min(r.X, bounds.Right()-width))
+	r.Y = max(bounds.Y, min(r.Y, bounds.Bottom()-height))
+	return r, fits
+}
+
+// placeNextTo returns the rect an overlay of the size gets next to the anchor.
+// When it doesn't fit on the side, the opposite side is tried, then the
+// other two. If none fit, it stays on the side but inside the bounds.
+func placeNextTo(anchor Rect, width, height int16, side Side, bounds Rect) Rect {
+	across := side ^ 2
+	for _, s := range [...]Side{side, side.opposite(), across, across.opposite()} {
+		if r, fits := sideRect(anchor, width, height, s, bounds); fits {
+			return r
+		}
+	}
+	r, _ := sideRect(anchor, width, height, side, bounds)
+	return r
+}
+
+// moves the box and everything in it
+func (b *Box) translate(dx, dy int16) {
+	b.x += dx
+	b.y += dy
+	for _, p := range b.children {
+		p.translate(dx, dy)
+	}
+}
+
+// placeOverlays moves the overlays next to their anchors and modals over the root,
+// once every box is laid out. They are placed in the order they were declared,
+// so an overlay can be anchored to a box inside an earlier one.
+func (l *Layout) placeOverlays() {
+	bounds := l.rootBox.Rect()
+	for _, p := range l.rootBox.children {
+		switch {
+		case p.modal == p:
+			p.translate(bounds.X-p.x, bounds.Y-p.y)
+			p.centerChildren()
+		case p.anchor != nil:
+			anchor := p.anchor.Rect()
+			if p.atPoint {
+				anchor = p.point
+			}
+			r := placeNextTo(anchor, int16(p.width), int16(p.height), p.side, bounds)
+			p.translate(r.X-p.x, r.Y-p.y)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package gala
+
+import "testing"
+
+func TestPlaceNextTo(t *testing.T) {
+	bounds := Rect{0, 0, 400, 300}
+	tests := []struct {
+		name   string
+		anchor Rect
+		width  int16
+		height int16
+		side   Side
+		want   Rect
+	}{
+		{"below", Rect{100, 100, 80, 20}, 120, 60, SideBottom, Rect{100, 120, 120, 60}},
+		{"above", Rect{100, 100, 80, 20}, 120, 60, SideTop, Rect{100, 40, 120, 60}},
+		{"right", Rect{100, 100, 80, 20}, 120, 60, SideRight, Rect{180, 100, 120, 60}},
+		{"left", Rect{200, 100, 80, 20}, 120, 60, SideLeft, Rect{80, 100, 120, 60}},
+		{"flips above at the bottom", Rect{100, 260, 80, 20}, 120, 60, SideBottom, Rect{100, 200, 120, 60}},
+		{"flips below at the top", Rect{100, 10, 80, 20}, 120, 60, SideTop, Rect{100, 30, 120, 60}},
+		{"flips left at the right", Rect{300, 100, 80, 20}, 120, 60, SideRight, Rect{180, 100, 120, 60}},
+		{"kept inside across", Rect{350, 100, 40, 20}, 120, 60, SideBottom, Rect{280, 120, 120, 60}},
+		{"tries the sides across", Rect{0, 100, 100, 100}, 120, 250, SideBottom, Rect{100, 50, 120, 250}},
+		{"none fit", Rect{0, 120, 400, 60}, 100, 150, SideBottom, Rect{0, 150, 100, 150}},
+	}
+	for _, test := range tests {
+		got := placeNextTo(test.anchor, test.width, test.height, test.side, bounds)
+		if got != test.want {
+			t.Errorf("%s: placeNextTo = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestOverlaysKeepIds(t *testing.T) {
+	l, r := newTestLayout()
+	frame := func(overlay bool) ID {
+		anchor := l.Box().Size(10, 10)
+		if overlay {
+			l.Box().Overlay(anchor, SideBottom).Size(50, 50)
+		}
+		// no Id, so its ID comes from its index in the root
+		box := l.Box().Size(20, 20)
+		l.End(r)
+		return box.ID()
+	}
+	without := frame(false)
+	if with := frame(true); with != without {
+		t.Errorf("the overlay changed the ID of the box after it, from %d to %d", without, with)
+	}
+	if r := l.lastRoots; r[len(r)-1].anchor == nil {
+		t.Error("the overlay isn't the last child of the root")
+	}
+}
+
+func TestOverlayAt(t *testing.T) {
+	l, r := newTestLayout()
+	input := &testInput{}
+	l.UseInput(input)
+	r.mouseX, r.mouseY = 110, 110
+	var under, overlay *Box
+	frame := func(x, y int16) {
+		under = l.Box().Id("under").Size(200, 200)
+		overlay = l.Box().OverlayAt(x, y).PassThrough().Size(100, 50)
+		l.End(r)
+	}
+	tests := []struct {
+		name string
+		x, y int16
+		want Rect
+	}{
+		{"at the point", 100, 100, Rect{100, 100, 100, 50}},
+		{"upwards at the bottom", 100, 580, Rect{100, 530, 100, 50}},
+		{"kept inside at the right", 750, 100, Rect{700, 100, 100, 50}},
+	}
+	for _, test := range tests {
+		frame(test.x, test.y)
+		if got := overlay.Rect(); got != test.want {
+			t.Errorf("%s: placed at %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	frame(100, 100)
+	frame(100, 100)
+	if !l.Hovered(under.ID()) || l.Hovered(overlay.ID()) {
+		t.Error("the pointer didn't pass through the overlay to the box under it")
+	}
+}
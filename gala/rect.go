@@ -1 +1,34 @@
 package gala
+
+// Rect is the computed position and size of a box, in pixels.
+type Rect struct {
+	X, Y, Width, Height int16
+}
+
+func (r Rect) Right() int16  { return r.X + r.Width }
+func (r Rect) Bottom() int16 { return r.Y + r.Height }
+
+func (r Rect) center() (int16, int16) {
+	return r.X + r.Width/2, r.Y + r.Height/2
+}
+
+// Contains reports whether the point is inside the rect.
+func (r Rect) Contains(x, y int32) bool {
+	return int32(r.X) <= x && int32(r.Right()) >= x &&
+		int32(r.Y) <= y && int32(r.Bottom()) >= y
+}
+
+// Rect returns the computed rect of the box, it's only valid after End.
+func (b *Box) Rect() Rect {
+	return Rect{b.x, b.y, int16(b.width), int16(b.height)}
+}
+
+// the rect inside the padding of the box
+func (b *Box) contentRect() Rect {
+	r := b.Rect()
+	r.X += b.padding.left
+	r.Y += b.padding.top
+	r.Width = max(0, r.Width-b.padding.left-b.padding.right)
+	r.Height = max(0, r.Height-b.padding.top-b.padding.bottom)
+	return r
+}
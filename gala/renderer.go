@@ -4,5 +4,42 @@ import "image/color"
 
 type Renderer interface {
 	DrawRect(poxX, posY, width, height int32, color color.RGBA)
+	// draws the sides of the border inside the rect
+	DrawBorder(posX, posY, width, height int32, border Border)
+	DrawText(text string, posX, posY, fontSize int32, color color.RGBA)
+	MeasureText(text string, fontSize int32) int32
+	// everything drawn until EndScissor is clipped to the rect
+	BeginScissor(posX, posY, width, height int32)
+	EndScissor()
 	MousePos() (x int32, y int32)
 }
+
+// RoundedRenderer is implemented by renderers that can draw rounded rects,
+// boxes with a BorderRadius are drawn square by the others.
+type RoundedRenderer interface {
+	DrawRoundedRect(posX, posY, width, height, radius int32, color color.RGBA)
+}
+
+// Input provides the keyboard state, it's usually implemented by the renderer.
+type Input interface {
+	// reports whether the key is currently held down
+	KeyDown(key Key) bool
+	// returns the next key pressed this frame, KeyNull when there are none left
+	NextKey() Key
+	// reports whether the key is held long enough to repeat this frame
+	KeyRepeated(key Key) bool
+	// returns the next character typed this frame, 0 when there are none left
+	NextChar() rune
+	MouseDown(button MouseButton) bool
+	MousePressed(button MouseButton) bool
+	// how far the mouse wheel moved this frame, positive is up
+	MouseWheel() float32
+	// reports whether the button of the first gamepad was pressed this frame
+	GamepadPressed(button GamepadButton) bool
+}
+
+// Clipboard is used by text inputs for copy and paste.
+type Clipboard interface {
+	ClipboardText() string
+	SetClipboardText(text string)
+}
@@ -0,0 +1,86 @@
+package gala
+
+import (
+	"image/color"
+	"slices"
+	"unicode/utf8"
+)
+
+// testRenderer draws nothing, every character is half the font size wide
+type testRenderer struct {
+	mouseX, mouseY int32
+}
+
+func (r *testRenderer) DrawRect(posX, posY, width, height int32, color color.RGBA)     {}
+func (r *testRenderer) DrawBorder(posX, posY, width, height int32, border Border)      {}
+func (r *testRenderer) DrawText(text string, posX, posY, fontSize int32, c color.RGBA) {}
+func (r *testRenderer) BeginScissor(posX, posY, width, height int32)                   {}
+func (r *testRenderer) EndScissor()                                                    {}
+
+func (r *testRenderer) MeasureText(text string, fontSize int32) int32 {
+	return int32(utf8.RuneCountInString(text)) * fontSize / 2
+}
+
+func (r *testRenderer) MousePos() (x int32, y int32) {
+	return r.mouseX, r.mouseY
+}
+
+// newTestLayout returns a layout of 800 by 600 pixels that measures text
+// with a testRenderer before the first End
+func newTestLayout() (*Layout, *testRenderer) {
+	l := NewLayout(800, 600, 64)
+	r := &testRenderer{}
+	l.renderer = r
+	return &l, r
+}
+
+// testInput has the mouse wheel moved by wheel every frame. The keys are
+// pressed and the chars typed in the next frame, once, the keys in down
+// and the left mouse button are held until they're changed.
+type testInput struct {
+	wheel        float32
+	keys         []Key
+	chars        []rune
+	down         []Key
+	mouseDown    bool
+	mousePressed bool // the left button went down this frame
+}
+
+func (i *testInput) NextKey() Key {
+	if len(i.keys) == 0 {
+		return KeyNull
+	}
+	key := i.keys[0]
+	i.keys = i.keys[1:]
+	return key
+}
+
+func (i *testInput) NextChar() rune {
+	if len(i.chars) == 0 {
+		return 0
+	}
+	char := i.chars[0]
+	i.chars = i.chars[1:]
+	return char
+}
+
+func (i *testInput) KeyDown(key Key) bool              { return slices.Contains(i.down, key) }
+func (i *testInput) KeyRepeated(key Key) bool          { return false }
+func (i *testInput) MouseDown(button MouseButton) bool { return button == MouseLeft && i.mouseDown }
+func (i *testInput) MousePressed(button MouseButton) bool {
+	return button == MouseLeft && i.mousePressed
+}
+func (i *testInput) MouseWheel() float32                      { return i.wheel }
+func (i *testInput) GamepadPressed(button GamepadButton) bool { return false }
+
+// click presses the left button over the point in one frame
+// and releases it in the next one
+func click(l *Layout, r *testRenderer, in *testInput, x, y int32, declare func()) {
+	r.mouseX, r.mouseY = x, y
+	in.mouseDown, in.mousePressed = true, true
+	declare()
+	l.End(r)
+	in.mouseDown, in.mousePressed = false, false
+	declare()
+	l.End(r)
+}
@@ -0,0 +1,221 @@
+package gala
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+Snapshot is a box of the tree laid out by End, with its children.
+Style holds the properties that were declared on the box, once tokens,
+stylesheets and variants were applied, as CSS declarations that only
+list what differs from a new box. The rest is where End placed it.
+
+It's meant to be encoded with encoding/json, for bug reports, golden
+files and external tools, and to be loaded back with LoadSnapshot.
+*/
+type Snapshot struct {
+	Id       string     `json:"id,omitempty"`
+	Hash     ID         `json:"hash"`
+	Class    string     `json:"class,omitempty"`
+	Text     string     `json:"text,omitempty"`
+	Style    string     `json:"style,omitempty"`
+	X        int16      `json:"x"`
+	Y        int16      `json:"y"`
+	Width    int16      `json:"width"`
+	Height   int16      `json:"height"`
+	ZIndex   int16      `json:"zIndex,omitempty"`
+	Children []Snapshot `json:"children,omitempty"`
+}
+
+// Snapshot returns the tree of the last End, starting at the root box.
+// It has to be taken before the first box of the next frame is declared.
+func (l *Layout) Snapshot() Snapshot {
+	// the children of the root are cleared at the end of End
+	s := snapshot(&l.rootBox)
+	// the root isn't a new box, its size is the screen's
+	s.Style = ""
+	for _, child := range l.lastRoots {
+		s.Children = append(s.Children, snapshot(child))
+	}
+	return s
+}
+
+func snapshot(b *Box) Snapshot {
+	r := b.Rect()
+	s := Snapshot{
+		Id:     b.id,
+		Hash:   b.hash,
+		Class:  b.class,
+		Text:   b.text,
+		Style:  declaredStyle(b.declared).String(),
+		X:      r.X,
+		Y:      r.Y,
+		Width:  r.Width,
+		Height: r.Height,
+		ZIndex: b.zindex,
+	}
+	for _, child := range b.children {
+		s.Children = append(s.Children, snapshot(child))
+	}
+	return s
+}
+
+/*
+LoadSnapshot declares the box of the snapshot and its children with their
+id, class, text and style, where they were placed is left to End.
+To declare the whole tree of a Snapshot of a layout, load the children of
+its root.
+*/
+func (l *Layout) LoadSnapshot(s Snapshot) (*Box, error) {
+	style, err := ParseStyle(s.Style)
+	if err != nil {
+		return nil, fmt.Errorf("style of %q: %w", s.Id, err)
+	}
+	box := l.Box().Id(s.Id).Class(s.Class).Text(s.Text).Apply(style)
+	for _, child := range s.Children {
+		c, err := l.LoadSnapshot(child)
+		if err != nil {
+			return nil, err
+		}
+		box.Contains(c)
+	}
+	return box, nil
+}
+
+// the style of a new box, declared styles only list what differs from it
+var defaultStyle = func() baseStyle {
+	var b Box
+	b.reset()
+	return b.baseStyle
+}()
+
+// declaredStyle returns a Style that sets what differs from a new box
+func declaredStyle(b baseStyle) Style {
+	d := &defaultStyle
+	s := Style{baseStyle: b}
+	flags := [...]struct {
+		property styleProperty
+		differs  bool
+	}{
+		{styleWidth, b.width != d.width},
+		{styleHeight, b.height != d.height},
+		{styleFlex, b.flex != d.flex},
+		{styleZIndex, b.zindex != d.zindex},
+		{styleLeft, b.left != d.left},
+		{styleRight, b.right != d.right},
+		{styleTop, b.top != d.top},
+		{styleBottom, b.bottom != d.bottom},
+		{stylePaddingLeft, b.padding.left != d.padding.left},
+		{stylePaddingRight, b.padding.right != d.padding.right},
+		{stylePaddingTop, b.padding.top != d.padding.top},
+		{stylePaddingBottom, b.padding.bottom != d.padding.bottom},
+		{styleMarginLeft, b.margin.left != d.margin.left},
+		{styleMarginRight, b.margin.right != d.margin.right},
+		{styleMarginTop, b.margin.top != d.margin.top},
+		{styleMarginBottom, b.margin.bottom != d.margin.bottom},
+		{stylePosition, b.position != d.position},
+		{styleDisplay, b.display != d.display},
+		{styleFlexDirection, b.flexDirection != d.flexDirection},
+		{styleJustifyContent, b.justifyContent != d.justifyContent},
+		{styleBackgroundColor, b.backgroundColor != d.backgroundColor},
+		{styleOverflow, b.overflow != d.overflow},
+		{styleFontSize, b.fontSize != d.fontSize},
+		{styleTextColor, b.textColor != d.textColor},
+		{styleBorderRadius, b.radius != d.radius},
+	}
+	for _, f := range flags {
+		if f.differs {
+			s.set |= f.property
+		}
+	}
+	sides, defaults := s.border.sides(), d.border.sides()
+	for i, p := range borderProperties {
+		if sides[i].Width != defaults[i].Width || sides[i].Color != defaults[i].Color {
+			s.set |= p.side
+		}
+		if sides[i].Style != defaults[i].Style {
+			s.set |= p.style
+		}
+	}
+	for _, a := range flexAligns {
+		if b.alignBits.has(a.self) && !d.alignBits.has(a.self) {
+			s = s.alignSelf(a.align)
+		}
+		if b.alignBits.has(a.items) && !d.alignBits.has(a.items) {
+			s = s.alignItems(a.align)
+		}
+	}
+	return s
+}
+
+// String returns the properties the style sets as CSS declarations,
+// in the same order every time, they can be parsed with ParseStyle.
+func (s Style) String() string {
+	var decls []string
+	add := func(p styleProperty, name, value string) {
+		if s.has(p) {
+			decls = append(decls, name+": "+value)
+		}
+	}
+	px := func(i int16) string { return strconv.Itoa(int(i)) + "px" }
+	size := func(f float32) string {
+		if f < 0 {
+			return strconv.Itoa(int(math.Round(float64(-f*100)))) + "%"
+		}
+		if f == 0 {
+			return "auto"
+		}
+		return strconv.Itoa(int(f)) + "px"
+	}
+	keywords := func(values ...string) func(int8) string {
+		return func(i int8) string { return values[i] }
+	}
+	aligns := keywords("flex-start", "center", "flex-end", "stretch")
+
+	add(styleDisplay, "display", keywords("flex", "none")(int8(s.display)))
+	add(stylePosition, "position", keywords("absolute", "relative")(int8(s.position)))
+	add(styleFlexDirection, "flex-direction", keywords("row", "column")(int8(s.flexDirection)))
+	add(styleJustifyContent, "justify-content", keywords("flex-start", "center", "flex-end",
+		"space-between", "space-around", "space-evenly")(int8(s.justifyContent)))
+	add(styleAlignItems, "align-items", aligns(int8(s.itemsAlign)))
+	add(styleAlignSelf, "align-self", aligns(int8(s.selfAlign)))
+	add(styleFlex, "flex", strconv.Itoa(int(s.flex)))
+	add(styleWidth, "width", size(s.width))
+	add(styleHeight, "height", size(s.height))
+	add(styleLeft, "left", px(s.left))
+	add(styleRight, "right", px(s.right))
+	add(styleTop, "top", px(s.top))
+	add(styleBottom, "bottom", px(s.bottom))
+	add(styleZIndex, "z-index", strconv.Itoa(int(s.zindex)))
+	add(stylePaddingTop, "padding-top", px(s.padding.top))
+	add(stylePaddingRight, "padding-right", px(s.padding.right))
+	add(stylePaddingBottom, "padding-bottom", px(s.padding.bottom))
+	add(stylePaddingLeft, "padding-left", px(s.padding.left))
+	add(styleMarginTop, "margin-top", px(s.margin.top))
+	add(styleMarginRight, "margin-right", px(s.margin.right))
+	add(styleMarginBottom, "margin-bottom", px(s.margin.bottom))
+	add(styleMarginLeft, "margin-left", px(s.margin.left))
+	add(styleOverflow, "overflow", keywords("visible", "hidden")(int8(s.overflow)))
+	add(styleBackgroundColor, "background-color", hexColor(s.backgroundColor))
+	add(styleTextColor, "color", hexColor(s.textColor))
+	add(styleFontSize, "font-size", px(s.fontSize))
+	add(styleBorderRadius, "border-radius", px(s.radius))
+	for i, side := range s.border.sides() {
+		p := borderProperties[i]
+		if s.has(p.side) || s.has(p.style) {
+			name := [...]string{"border-top", "border-right", "border-bottom", "border-left"}[i]
+			value := px(side.Width) + " " + keywords("solid", "dashed", "dotted")(int8(side.Style)) +
+				" " + hexColor(side.Color)
+			decls = append(decls, name+": "+value)
+		}
+	}
+	return strings.Join(decls, "; ")
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
@@ -0,0 +1,88 @@
+package gala
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// declares a tree that uses most properties
+func declareSnapshotTree(l *Layout) {
+	l.Box().Id("App").FlexDirection_Column().Size(Percent(100), Percent(100)).Contains(
+		l.Box().Id("Header").Class("bar").Height(30).Padding(4).
+			BackgroundColor(color.RGBA{52, 52, 58, 255}).
+			Contains(l.Box().Text("Title").FontSize(20).TextColor(color.RGBA{255, 255, 255, 255})),
+		l.Box().Id("Body").Height(200).FlexDirection_Row().Contains(
+			l.Box().Width(100).Margin(2).BorderRadius(4).Border(1, color.RGBA{88, 101, 242, 255}),
+			l.Box().Flex(1).Overflow_Hidden().BorderBottom(2, color.RGBA{255, 0, 0, 255}).BorderStyle_Dashed(),
+			l.Box().Position_Absolute().Right(10).Bottom(10).Size(20, 20).ZIndex(3),
+		),
+	)
+}
+
+func TestSnapshotGolden(t *testing.T) {
+	l, r := newTestLayout()
+	declareSnapshotTree(l)
+	l.End(r)
+	got, err := json.MarshalIndent(l.Snapshot(), "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", "snapshot.json")
+	if *update {
+		if err := os.WriteFile(golden, append(got, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(append(got, '\n'), want) {
+		t.Errorf("the snapshot differs from %s, run the tests with -update if it's expected:\n%s", golden, got)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	l, r := newTestLayout()
+	declareSnapshotTree(l)
+	l.End(r)
+	data, err := json.Marshal(l.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var loaded Snapshot
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	for _, child := range loaded.Children {
+		if _, err := l.LoadSnapshot(child); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.End(r)
+	again, err := json.Marshal(l.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("the loaded tree differs\n got %s\nwant %s", again, data)
+	}
+}
+
+func TestLoadSnapshotError(t *testing.T) {
+	l, r := newTestLayout()
+	_, err := l.LoadSnapshot(Snapshot{Id: "Box", Style: "width: wide"})
+	l.End(r)
+	want := `style of "Box": 1:8: width: expected a length in pixels, found "wide"`
+	if err == nil || err.Error() != want {
+		t.Errorf("error %v, want %s", err, want)
+	}
+}
@@ -0,0 +1,64 @@
+package gala
+
+import "reflect"
+
+// states are kept per id and type, so different widgets
+// can keep state for the same box.
+type stateKey struct {
+	id ID
+	t  reflect.Type
+}
+
+type stateEntry struct {
+	value any
+	frame uint64 // the last one it was asked for in
+}
+
+// states that weren't asked for in this many frames are dropped, about
+// 10 seconds at 60 frames per second. So are reported duplicate ids.
+const stateFrames = 600
+
+/*
+State returns the value of type T kept for the id across frames.
+It's created the first time it's asked for, and dropped once it hasn't
+been asked for in a while, like the state of a row that scrolled out of
+a VirtualList.
+
+	type counter struct{ clicks int }
+	gala.State[counter](&layout, box.ID()).clicks++
+*/
+func State[T any](l *Layout, id ID) *T {
+	if id == 0 {
+		// nothing to key it by, it won't survive the frame
+		return new(T)
+	}
+	if l.states == nil {
+		l.states = make(map[stateKey]*stateEntry)
+	}
+	key := stateKey{id, reflect.TypeFor[T]()}
+	if entry, ok := l.states[key]; ok {
+		entry.frame = l.frame
+		return entry.value.(*T)
+	}
+	s := new(T)
+	l.states[key] = &stateEntry{s, l.frame}
+	return s
+}
+
+// pruneStates drops the states and the reported duplicate ids that weren't
+// used in the last stateFrames frames, it only looks every stateFrames frames.
+func (l *Layout) pruneStates() {
+	if l.frame%stateFrames != 0 {
+		return
+	}
+	for key, entry := range l.states {
+		if l.frame-entry.frame >= stateFrames {
+			delete(l.states, key)
+		}
+	}
+	for id, frame := range l.reportedIds {
+		if l.frame-frame >= stateFrames {
+			delete(l.reportedIds, id)
+		}
+	}
+}
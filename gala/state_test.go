@@ -0,0 +1,24 @@
+package gala
+
+import "testing"
+
+func TestStatesAreDropped(t *testing.T) {
+	type counter struct{ n int }
+	l, r := newTestLayout()
+	const kept, dropped ID = 1, 2
+	State[counter](l, kept).n = 1
+	State[counter](l, dropped).n = 1
+	for range 2 * stateFrames {
+		State[counter](l, kept)
+		l.End(r)
+	}
+	if got := State[counter](l, kept).n; got != 1 {
+		t.Errorf("the state asked for every frame is %d, want it kept at 1", got)
+	}
+	if got := State[counter](l, dropped).n; got != 0 {
+		t.Errorf("the state that wasn't asked for is %d, want it dropped", got)
+	}
+	if len(l.states) != 2 {
+		t.Errorf("%d states, want the 2 asked for last", len(l.states))
+	}
+}
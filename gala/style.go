@@ -88,6 +88,12 @@ type baseStyle struct {
 	justifyContent  justifyContent
 	alignBits       alignProperties // Combined alignItems and alignSelf
 	backgroundColor color.RGBA
+
+	overflow  overflow
+	fontSize  int16
+	textColor color.RGBA
+	radius    int16 // of the corners of the background
+	border    Border
 }
 
 // set sets a property.
@@ -104,3 +110,392 @@ func (p alignProperties) has(k flexAlignKey) bool {
 func (p alignProperties) unset(k flexAlignKey) alignProperties {
 	return p &^ alignProperties(k) // Use bitwise AND NOT to unset the property
 }
+
+// the bits of every alignment, for alignSelf and alignItems
+var flexAligns = [...]struct {
+	self, items flexAlignKey
+	align       flexAlign
+}{
+	{selfAlignFlexStart, itemsAlignFlexStart, alignFlexStart},
+	{selfAlignCenter, itemsAlignCenter, alignCenter},
+	{selfAlignFlexEnd, itemsAlignFlexEnd, alignFlexEnd},
+	{selfAlignStretch, itemsAlignStretch, alignStretch},
+}
+
+// crossAlign is how the child is aligned across the direction of its parent,
+// its alignSelf overrides the alignItems of the parent.
+func crossAlign(parent, child *Box) flexAlign {
+	for _, a := range flexAligns {
+		if child.alignBits.has(a.self) {
+			return a.align
+		}
+	}
+	for _, a := range flexAligns {
+		if parent.alignBits.has(a.items) {
+			return a.align
+		}
+	}
+	return alignStretch
+}
+
+// the properties a Style sets, one bit each
+type styleProperty uint64
+
+const (
+	styleWidth styleProperty = 1 << iota
+	styleHeight
+	styleFlex
+	styleZIndex
+	styleLeft
+	styleRight
+	styleTop
+	styleBottom
+	stylePaddingLeft
+	stylePaddingRight
+	stylePaddingTop
+	stylePaddingBottom
+	styleMarginLeft
+	styleMarginRight
+	styleMarginTop
+	styleMarginBottom
+	stylePosition
+	styleDisplay
+	styleFlexDirection
+	styleJustifyContent
+	styleAlignSelf
+	styleAlignItems
+	styleBackgroundColor
+	styleOverflow
+	styleFontSize
+	styleTextColor
+	styleBorderRadius
+	// width and color of a side of the border
+	styleBorderTop
+	styleBorderRight
+	styleBorderBottom
+	styleBorderLeft
+	styleBorderTopStyle
+	styleBorderRightStyle
+	styleBorderBottomStyle
+	styleBorderLeftStyle
+)
+
+/*
+Style is a set of style properties that can be applied to boxes with
+Box.Apply. It's built with the same setters as a Box, but they return a
+new Style, so a style can be defined once and extended:
+
+	var Card = gala.Style{}.Padding(10).BackgroundColor(surface)
+	var SelectedCard = Card.BackgroundColor(accent)
+
+Only the properties that were set are applied, a property set to zero
+is applied as zero. The zero Style sets nothing.
+*/
+type Style struct {
+	baseStyle
+	selfAlign, itemsAlign flexAlign
+	set                   styleProperty
+}
+
+// Apply applies the properties set by the styles in order,
+// so later styles override earlier ones.
+func (b *Box) Apply(styles ...Style) *Box {
+	for i := range styles {
+		s := &styles[i]
+		s.copyTo(&b.baseStyle)
+		if s.has(styleAlignSelf) {
+			b.alignSelf(s.selfAlign)
+		}
+		if s.has(styleAlignItems) {
+			b.alignItems(s.itemsAlign)
+		}
+	}
+	return b
+}
+
+// Merge returns the style with the properties set by other on top.
+func (s Style) Merge(other Style) Style {
+	other.copyTo(&s.baseStyle)
+	if other.has(styleAlignSelf) {
+		s.selfAlign = other.selfAlign
+	}
+	if other.has(styleAlignItems) {
+		s.itemsAlign = other.itemsAlign
+	}
+	s.set |= other.set
+	return s
+}
+
+func (s *Style) has(p styleProperty) bool {
+	return s.set&p != 0
+}
+
+// copyTo copies the properties that are set, other than alignment
+func (s *Style) copyTo(dst *baseStyle) {
+	if s.set == 0 {
+		return
+	}
+	if s.has(styleWidth) {
+		dst.width = s.width
+	}
+	if s.has(styleHeight) {
+		dst.height = s.height
+	}
+	if s.has(styleFlex) {
+		dst.flex = s.flex
+	}
+	if s.has(styleZIndex) {
+		dst.zindex = s.zindex
+	}
+	if s.has(styleLeft) {
+		dst.left = s.left
+	}
+	if s.has(styleRight) {
+		dst.right = s.right
+	}
+	if s.has(styleTop) {
+		dst.top = s.top
+	}
+	if s.has(styleBottom) {
+		dst.bottom = s.bottom
+	}
+	if s.has(stylePaddingLeft) {
+		dst.padding.left = s.padding.left
+	}
+	if s.has(stylePaddingRight) {
+		dst.padding.right = s.padding.right
+	}
+	if s.has(stylePaddingTop) {
+		dst.padding.top = s.padding.top
+	}
+	if s.has(stylePaddingBottom) {
+		dst.padding.bottom = s.padding.bottom
+	}
+	if s.has(styleMarginLeft) {
+		dst.margin.left = s.margin.left
+	}
+	if s.has(styleMarginRight) {
+		dst.margin.right = s.margin.right
+	}
+	if s.has(styleMarginTop) {
+		dst.margin.top = s.margin.top
+	}
+	if s.has(styleMarginBottom) {
+		dst.margin.bottom = s.margin.bottom
+	}
+	if s.has(stylePosition) {
+		dst.position = s.position
+	}
+	if s.has(styleDisplay) {
+		dst.display = s.display
+	}
+	if s.has(styleFlexDirection) {
+		dst.flexDirection = s.flexDirection
+	}
+	if s.has(styleJustifyContent) {
+		dst.justifyContent = s.justifyContent
+	}
+	if s.has(styleBackgroundColor) {
+		dst.backgroundColor = s.backgroundColor
+	}
+	if s.has(styleOverflow) {
+		dst.overflow = s.overflow
+	}
+	if s.has(styleFontSize) {
+		dst.fontSize = s.fontSize
+	}
+	if s.has(styleTextColor) {
+		dst.textColor = s.textColor
+	}
+	if s.has(styleBorderRadius) {
+		dst.radius = s.radius
+	}
+	s.copyBorder(&dst.border)
+}
+
+// Setters, they match the ones of Box
+
+func (s Style) Padding(i int16) Style {
+	return s.PaddingLeft(i).PaddingRight(i).PaddingTop(i).PaddingBottom(i)
+}
+
+func (s Style) PaddingLeft(i int16) Style {
+	s.padding.left, s.set = i, s.set|stylePaddingLeft
+	return s
+}
+
+func (s Style) PaddingRight(i int16) Style {
+	s.padding.right, s.set = i, s.set|stylePaddingRight
+	return s
+}
+
+func (s Style) PaddingTop(i int16) Style {
+	s.padding.top, s.set = i, s.set|stylePaddingTop
+	return s
+}
+
+func (s Style) PaddingBottom(i int16) Style {
+	s.padding.bottom, s.set = i, s.set|stylePaddingBottom
+	return s
+}
+
+func (s Style) Margin(i int16) Style {
+	return s.MarginLeft(i).MarginRight(i).MarginTop(i).MarginBottom(i)
+}
+
+func (s Style) MarginLeft(i int16) Style {
+	s.margin.left, s.set = i, s.set|styleMarginLeft
+	return s
+}
+
+func (s Style) MarginRight(i int16) Style {
+	s.margin.right, s.set = i, s.set|styleMarginRight
+	return s
+}
+
+func (s Style) MarginTop(i int16) Style {
+	s.margin.top, s.set = i, s.set|styleMarginTop
+	return s
+}
+
+func (s Style) MarginBottom(i int16) Style {
+	s.margin.bottom, s.set = i, s.set|styleMarginBottom
+	return s
+}
+
+func (s Style) Position_Relative() Style {
+	s.position, s.set = positionRelative, s.set|stylePosition
+	return s
+}
+
+func (s Style) Position_Absolute() Style {
+	s.position, s.set = positionAbsolute, s.set|stylePosition
+	return s
+}
+
+func (s Style) Display_Flex() Style {
+	s.display, s.set = displayFlex, s.set|styleDisplay
+	return s
+}
+
+func (s Style) Display_None() Style {
+	s.display, s.set = displayNone, s.set|styleDisplay
+	return s
+}
+
+func (s Style) AlignSelf_FlexStart() Style { return s.alignSelf(alignFlexStart) }
+func (s Style) AlignSelf_Center() Style    { return s.alignSelf(alignCenter) }
+func (s Style) AlignSelf_FlexEnd() Style   { return s.alignSelf(alignFlexEnd) }
+func (s Style) AlignSelf_Stretch() Style   { return s.alignSelf(alignStretch) }
+
+func (s Style) AlignItems_FlexStart() Style { return s.alignItems(alignFlexStart) }
+func (s Style) AlignItems_Center() Style    { return s.alignItems(alignCenter) }
+func (s Style) AlignItems_FlexEnd() Style   { return s.alignItems(alignFlexEnd) }
+func (s Style) AlignItems_Stretch() Style   { return s.alignItems(alignStretch) }
+
+func (s Style) alignSelf(align flexAlign) Style {
+	s.selfAlign, s.set = align, s.set|styleAlignSelf
+	return s
+}
+
+func (s Style) alignItems(align flexAlign) Style {
+	s.itemsAlign, s.set = align, s.set|styleAlignItems
+	return s
+}
+
+func (s Style) JustifyContent_FlexStart() Style    { return s.justify(justifyFlexStart) }
+func (s Style) JustifyContent_Center() Style       { return s.justify(justifyCenter) }
+func (s Style) JustifyContent_FlexEnd() Style      { return s.justify(justifyFlexEnd) }
+func (s Style) JustifyContent_SpaceBetween() Style { return s.justify(justifySpaceBetween) }
+func (s Style) JustifyContent_SpaceAround() Style  { return s.justify(justifySpaceAround) }
+func (s Style) JustifyContent_SpaceEvenly() Style  { return s.justify(justifySpaceEvenly) }
+
+func (s Style) justify(j justifyContent) Style {
+	s.justifyContent, s.set = j, s.set|styleJustifyContent
+	return s
+}
+
+func (s Style) FlexDirection_Row() Style {
+	s.flexDirection, s.set = directionRow, s.set|styleFlexDirection
+	return s
+}
+
+func (s Style) FlexDirection_Column() Style {
+	s.flexDirection, s.set = directionColumn, s.set|styleFlexDirection
+	return s
+}
+
+func (s Style) Left(i int16) Style {
+	s.left, s.set = i, s.set|styleLeft
+	return s
+}
+
+func (s Style) Right(i int16) Style {
+	s.right, s.set = i, s.set|styleRight
+	return s
+}
+
+func (s Style) Top(i int16) Style {
+	s.top, s.set = i, s.set|styleTop
+	return s
+}
+
+func (s Style) Bottom(i int16) Style {
+	s.bottom, s.set = i, s.set|styleBottom
+	return s
+}
+
+// use gala.Percent() for percentages, like Box.Width
+func (s Style) Width(i float32) Style {
+	s.width, s.set = max(-1, i), s.set|styleWidth
+	return s
+}
+
+func (s Style) Height(i float32) Style {
+	s.height, s.set = max(-1, i), s.set|styleHeight
+	return s
+}
+
+func (s Style) Size(w, h float32) Style {
+	return s.Width(w).Height(h)
+}
+
+func (s Style) Flex(i int16) Style {
+	s.flex, s.set = i, s.set|styleFlex
+	return s
+}
+
+func (s Style) ZIndex(i int16) Style {
+	s.zindex, s.set = i, s.set|styleZIndex
+	return s
+}
+
+func (s Style) BackgroundColor(col color.RGBA) Style {
+	s.backgroundColor, s.set = col, s.set|styleBackgroundColor
+	return s
+}
+
+func (s Style) Overflow_Visible() Style {
+	s.overflow, s.set = overflowVisible, s.set|styleOverflow
+	return s
+}
+
+func (s Style) Overflow_Hidden() Style {
+	s.overflow, s.set = overflowHidden, s.set|styleOverflow
+	return s
+}
+
+func (s Style) FontSize(i int16) Style {
+	s.fontSize, s.set = max(1, i), s.set|styleFontSize
+	return s
+}
+
+func (s Style) TextColor(col color.RGBA) Style {
+	s.textColor, s.set = col, s.set|styleTextColor
+	return s
+}
+
+func (s Style) BorderRadius(i int16) Style {
+	s.radius, s.set = max(0, i), s.set|styleBorderRadius
+	return s
+}
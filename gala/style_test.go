@@ -0,0 +1,55 @@
+package gala
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestStyleLayering(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	base := func(l *Layout) *Box {
+		return l.Box().Padding(5).ZIndex(3).BackgroundColor(red).AlignItems_Center().AlignSelf_FlexEnd()
+	}
+	tests := []struct {
+		name   string
+		styles []Style
+		check  func(b *Box) bool
+	}{
+		{"the zero style sets nothing", []Style{{}}, func(b *Box) bool {
+			return b.padding.left == 5 && b.zindex == 3 && b.backgroundColor == red &&
+				b.alignBits.has(selfAlignFlexEnd) && b.alignBits.has(itemsAlignCenter)
+		}},
+		{"a property set to zero is applied", []Style{Style{}.Padding(0).ZIndex(0)}, func(b *Box) bool {
+			return b.padding.left == 0 && b.zindex == 0 && b.backgroundColor == red
+		}},
+		{"a zero color is applied", []Style{Style{}.BackgroundColor(color.RGBA{})}, func(b *Box) bool {
+			return b.backgroundColor == color.RGBA{} && b.padding.left == 5
+		}},
+		{"later styles override earlier ones", []Style{Style{}.ZIndex(7), Style{}.ZIndex(0)}, func(b *Box) bool {
+			return b.zindex == 0
+		}},
+		{"unset properties of later styles keep earlier ones", []Style{Style{}.ZIndex(7), Style{}.Padding(1)}, func(b *Box) bool {
+			return b.zindex == 7 && b.padding.left == 1
+		}},
+		{"alignment replaces the one before", []Style{Style{}.AlignItems_FlexStart().AlignSelf_Stretch()}, func(b *Box) bool {
+			return b.alignBits.has(itemsAlignFlexStart) && !b.alignBits.has(itemsAlignCenter) &&
+				b.alignBits.has(selfAlignStretch) && !b.alignBits.has(selfAlignFlexEnd)
+		}},
+		{"unset alignment is kept", []Style{Style{}.AlignItems_FlexStart()}, func(b *Box) bool {
+			return b.alignBits.has(itemsAlignFlexStart) && b.alignBits.has(selfAlignFlexEnd)
+		}},
+		{"merged styles layer the same way", []Style{Style{}.ZIndex(7).AlignItems_FlexEnd().Merge(Style{}.Padding(0))}, func(b *Box) bool {
+			return b.zindex == 7 && b.padding.left == 0 && b.alignBits.has(itemsAlignFlexEnd)
+		}},
+		{"merged zeros override", []Style{Style{}.ZIndex(7).Merge(Style{}.ZIndex(0)).Merge(Style{})}, func(b *Box) bool {
+			return b.zindex == 0 && b.padding.left == 5
+		}},
+	}
+	for _, test := range tests {
+		l, _ := newTestLayout()
+		box := base(l).Apply(test.styles...)
+		if !test.check(box) {
+			t.Errorf("%s: got %s", test.name, declaredStyle(box.baseStyle))
+		}
+	}
+}
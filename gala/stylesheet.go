@@ -0,0 +1,733 @@
+package gala
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+Stylesheet is a set of rules written in a subset of CSS, applied to the
+boxes of a layout with UseStylesheet.
+
+Selectors select boxes by their Id with #id and by their Class with
+.class, * selects every box. They can be combined, like #save.primary,
+and followed by :hover, :focus, :active or :disabled, which match the
+same states as the style variants. Selectors separated by spaces select
+descendants, and rules can have several selectors separated by commas.
+
+	#Card .title:hover { background-color: #1a1a1d; padding: 4px 8px }
+
+The supported properties are display, position, flex-direction, flex,
+justify-content, align-items, align-self, width, height, left, right,
+top, bottom, z-index, padding and margin with their shorthands and
+sides, overflow, background-color (or background), color, font-size,
+border-radius, and border with its sides, border-width, border-color and
+border-style, which is solid, dashed or dotted. Lengths are in pixels,
+with or without px, widths and heights can also be percentages. Colors
+are #rgb, #rgba, #rrggbb, #rrggbbaa, rgb(), rgba() or a few names like
+white and transparent.
+*/
+type Stylesheet struct {
+	rules []styleRule // by specificity, then in order
+}
+
+type styleRule struct {
+	selector    []compoundSelector // the last one selects the box, the others its ancestors
+	specificity int
+	style       Style
+}
+
+type compoundSelector struct {
+	id      string
+	classes []string
+	states  []interactionState
+}
+
+// ParseError is returned for stylesheets and markup that can't be parsed,
+// Line and Column start at 1.
+type ParseError struct {
+	File         string // empty when the source isn't a file
+	Line, Column int
+	Message      string
+}
+
+func (e *ParseError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// ParseStylesheet parses the rules of the stylesheet,
+// the error is a *ParseError.
+func ParseStylesheet(src string) (*Stylesheet, error) {
+	p := cssParser{src: src, line: 1, col: 1}
+	sheet := &Stylesheet{}
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.eof() {
+			break
+		}
+		selectors, err := p.selectors()
+		if err != nil {
+			return nil, err
+		}
+		style, err := p.declarations()
+		if err != nil {
+			return nil, err
+		}
+		for _, selector := range selectors {
+			sheet.rules = append(sheet.rules, styleRule{selector, specificity(selector), style})
+		}
+	}
+	sort.SliceStable(sheet.rules, func(i, j int) bool {
+		return sheet.rules[i].specificity < sheet.rules[j].specificity
+	})
+	return sheet, nil
+}
+
+// ParseStyle parses declarations like the ones of a rule, without the
+// braces, into a Style, like "padding: 4px; color: white".
+// The error is a *ParseError.
+func ParseStyle(declarations string) (Style, error) {
+	p := cssParser{src: declarations, line: 1, col: 1, inline: true}
+	return p.declarations()
+}
+
+// ids count more than classes and states, like in CSS
+func specificity(selector []compoundSelector) int {
+	n := 0
+	for _, c := range selector {
+		if c.id != "" {
+			n += 100
+		}
+		n += len(c.classes) + len(c.states)
+	}
+	return n
+}
+
+// UseStylesheet sets the stylesheet applied to the boxes during End,
+// nil removes it. Its rules are applied over the style set in code and
+// the tokens, and under the style variants.
+func (l *Layout) UseStylesheet(sheet *Stylesheet) {
+	l.stylesheet = sheet
+}
+
+// applyStylesheet applies the matching rules to every box, it runs
+// once the tree is known and before the boxes are sized
+func (l *Layout) applyStylesheet() {
+	if l.stylesheet == nil {
+		return
+	}
+	for i := range l.stylesheet.rules {
+		rule := &l.stylesheet.rules[i]
+		for _, box := range l.secondQueue {
+			if l.matches(box, rule.selector) {
+				box.Apply(rule.style)
+			}
+		}
+	}
+}
+
+// matches reports whether the box matches the last part of the selector
+// and its ancestors the parts before it, in order
+func (l *Layout) matches(box *Box, selector []compoundSelector) bool {
+	last := len(selector) - 1
+	if !l.matchesCompound(box, &selector[last]) {
+		return false
+	}
+	i := last - 1
+	for p := box.parent; p != nil && i >= 0; p = p.parent {
+		if l.matchesCompound(p, &selector[i]) {
+			i--
+		}
+	}
+	return i < 0
+}
+
+func (l *Layout) matchesCompound(box *Box, c *compoundSelector) bool {
+	if c.id != "" && box.id != c.id {
+		return false
+	}
+	for _, class := range c.classes {
+		if !hasClass(box.class, class) {
+			return false
+		}
+	}
+	for _, state := range c.states {
+		if !l.inState(box, state) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasClass(classes, class string) bool {
+	for rest := classes; rest != ""; {
+		var name string
+		name, rest, _ = strings.Cut(strings.TrimLeft(rest, " "), " ")
+		if name == class {
+			return true
+		}
+	}
+	return false
+}
+
+type cssParser struct {
+	src       string
+	pos       int
+	line, col int  // of pos
+	inline    bool // declarations without braces, they end with the source
+}
+
+func (p *cssParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *cssParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *cssParser) next() byte {
+	c := p.peek()
+	p.pos++
+	p.col++
+	if c == '\n' {
+		p.line, p.col = p.line+1, 1
+	}
+	return c
+}
+
+func (p *cssParser) errorAt(line, col int, format string, args ...any) error {
+	return &ParseError{Line: line, Column: col, Message: fmt.Sprintf(format, args...)}
+}
+
+// skipSpace skips whitespace and comments
+func (p *cssParser) skipSpace() error {
+	for !p.eof() {
+		switch {
+		case isSpace(p.peek()):
+			p.next()
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			line, col := p.line, p.col
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end == -1 {
+				return p.errorAt(line, col, "unterminated comment")
+			}
+			for range end + 4 {
+				p.next()
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isIdent(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+func (p *cssParser) ident() string {
+	start := p.pos
+	for !p.eof() && isIdent(p.peek()) {
+		p.next()
+	}
+	return p.src[start:p.pos]
+}
+
+// selectors parses the selectors of a rule and the { after them
+func (p *cssParser) selectors() ([][]compoundSelector, error) {
+	var list [][]compoundSelector
+	var current []compoundSelector
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		switch c := p.peek(); {
+		case p.eof():
+			return nil, p.errorAt(p.line, p.col, "unexpected end of stylesheet, expected {")
+		case c == '{' || c == ',':
+			if len(current) == 0 {
+				return nil, p.errorAt(p.line, p.col, "expected a selector before %q", c)
+			}
+			list, current = append(list, current), nil
+			p.next()
+			if c == '{' {
+				return list, nil
+			}
+		case c == '>' || c == '+' || c == '~':
+			return nil, p.errorAt(p.line, p.col, "only descendant combinators are supported, found %q", c)
+		default:
+			compound, err := p.compound()
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, compound)
+		}
+	}
+}
+
+// compound parses a selector without combinators, like #id.class:hover
+func (p *cssParser) compound() (compoundSelector, error) {
+	var c compoundSelector
+	start := p.pos
+	for !p.eof() {
+		line, col := p.line, p.col
+		switch p.peek() {
+		case '*':
+			p.next()
+			continue
+		case '#', '.', ':':
+		default:
+			if p.pos == start {
+				return c, p.errorAt(line, col, "unexpected %q in selector", p.peek())
+			}
+			return c, nil
+		}
+		kind := p.next()
+		name := p.ident()
+		if name == "" {
+			return c, p.errorAt(p.line, p.col, "expected a name after %q", kind)
+		}
+		switch kind {
+		case '#':
+			if c.id != "" && c.id != name {
+				return c, p.errorAt(line, col, "a box has a single id")
+			}
+			c.id = name
+		case '.':
+			c.classes = append(c.classes, name)
+		case ':':
+			state, ok := pseudoClasses[name]
+			if !ok {
+				return c, p.errorAt(line, col, "unknown pseudo-class :%s", name)
+			}
+			c.states = append(c.states, state)
+		}
+	}
+	return c, nil
+}
+
+var pseudoClasses = map[string]interactionState{
+	"hover":    stateHover,
+	"focus":    stateFocus,
+	"active":   stateActive,
+	"disabled": stateDisabled,
+}
+
+// declarations parses the properties of a rule up to the closing }
+func (p *cssParser) declarations() (Style, error) {
+	var style Style
+	for {
+		if err := p.skipSpace(); err != nil {
+			return style, err
+		}
+		if p.eof() && p.inline {
+			return style, nil
+		}
+		if p.eof() {
+			return style, p.errorAt(p.line, p.col, "unexpected end of stylesheet, expected }")
+		}
+		if p.peek() == '}' && !p.inline {
+			p.next()
+			return style, nil
+		}
+		if p.peek() == ';' {
+			p.next()
+			continue
+		}
+		line, col := p.line, p.col
+		name := p.ident()
+		if name == "" {
+			return style, p.errorAt(line, col, "expected a property, found %q", p.peek())
+		}
+		property, ok := cssProperties[strings.ToLower(name)]
+		if !ok {
+			return style, p.errorAt(line, col, "unknown property %q", name)
+		}
+		if err := p.skipSpace(); err != nil {
+			return style, err
+		}
+		if p.peek() != ':' {
+			return style, p.errorAt(p.line, p.col, "expected : after %q", name)
+		}
+		p.next()
+		if err := p.skipSpace(); err != nil {
+			return style, err
+		}
+		line, col = p.line, p.col
+		start := p.pos
+		for !p.eof() && p.peek() != ';' && p.peek() != '}' {
+			p.next()
+		}
+		value := strings.TrimSpace(p.src[start:p.pos])
+		if value == "" {
+			return style, p.errorAt(line, col, "expected a value for %q", name)
+		}
+		var err error
+		if style, err = property(style, value); err != nil {
+			return style, p.errorAt(line, col, "%s: %v", name, err)
+		}
+	}
+}
+
+// the properties by name, they set the value on the style
+var cssProperties = map[string]func(s Style, value string) (Style, error){
+	"display": keyword(map[string]func(Style) Style{
+		"flex": Style.Display_Flex,
+		"none": Style.Display_None,
+	}),
+	"position": keyword(map[string]func(Style) Style{
+		"relative": Style.Position_Relative,
+		"absolute": Style.Position_Absolute,
+	}),
+	"flex-direction": keyword(map[string]func(Style) Style{
+		"row":    Style.FlexDirection_Row,
+		"column": Style.FlexDirection_Column,
+	}),
+	"justify-content": keyword(map[string]func(Style) Style{
+		"flex-start":    Style.JustifyContent_FlexStart,
+		"center":        Style.JustifyContent_Center,
+		"flex-end":      Style.JustifyContent_FlexEnd,
+		"space-between": Style.JustifyContent_SpaceBetween,
+		"space-around":  Style.JustifyContent_SpaceAround,
+		"space-evenly":  Style.JustifyContent_SpaceEvenly,
+	}),
+	"align-items": keyword(map[string]func(Style) Style{
+		"flex-start": Style.AlignItems_FlexStart,
+		"center":     Style.AlignItems_Center,
+		"flex-end":   Style.AlignItems_FlexEnd,
+		"stretch":    Style.AlignItems_Stretch,
+	}),
+	"align-self": keyword(map[string]func(Style) Style{
+		"flex-start": Style.AlignSelf_FlexStart,
+		"center":     Style.AlignSelf_Center,
+		"flex-end":   Style.AlignSelf_FlexEnd,
+		"stretch":    Style.AlignSelf_Stretch,
+	}),
+	"overflow": keyword(map[string]func(Style) Style{
+		"visible": Style.Overflow_Visible,
+		"hidden":  Style.Overflow_Hidden,
+	}),
+	"flex":          integer(Style.Flex),
+	"z-index":       integer(Style.ZIndex),
+	"width":         size(Style.Width),
+	"height":        size(Style.Height),
+	"left":          length(Style.Left),
+	"right":         length(Style.Right),
+	"top":           length(Style.Top),
+	"bottom":        length(Style.Bottom),
+	"font-size":     length(Style.FontSize),
+	"border-radius": length(Style.BorderRadius),
+
+	"padding":        sides(Style.PaddingTop, Style.PaddingRight, Style.PaddingBottom, Style.PaddingLeft),
+	"padding-top":    length(Style.PaddingTop),
+	"padding-right":  length(Style.PaddingRight),
+	"padding-bottom": length(Style.PaddingBottom),
+	"padding-left":   length(Style.PaddingLeft),
+	"margin":         sides(Style.MarginTop, Style.MarginRight, Style.MarginBottom, Style.MarginLeft),
+	"margin-top":     length(Style.MarginTop),
+	"margin-right":   length(Style.MarginRight),
+	"margin-bottom":  length(Style.MarginBottom),
+	"margin-left":    length(Style.MarginLeft),
+
+	"border":        borderSides(0, 1, 2, 3),
+	"border-top":    borderSides(0),
+	"border-right":  borderSides(1),
+	"border-bottom": borderSides(2),
+	"border-left":   borderSides(3),
+	"border-width":  borderWidths,
+	"border-color":  borderColor,
+	"border-style":  borderStyle,
+
+	"background":       colorValue(Style.BackgroundColor),
+	"background-color": colorValue(Style.BackgroundColor),
+	"color":            colorValue(Style.TextColor),
+}
+
+func keyword(values map[string]func(Style) Style) func(Style, string) (Style, error) {
+	return func(s Style, value string) (Style, error) {
+		set, ok := values[strings.ToLower(value)]
+		if !ok {
+			return s, fmt.Errorf("unknown value %q", value)
+		}
+		return set(s), nil
+	}
+}
+
+func integer(set func(Style, int16) Style) func(Style, string) (Style, error) {
+	return func(s Style, value string) (Style, error) {
+		i, err := strconv.ParseInt(value, 10, 16)
+		if err != nil {
+			return s, fmt.Errorf("expected an integer, found %q", value)
+		}
+		return set(s, int16(i)), nil
+	}
+}
+
+func length(set func(Style, int16) Style) func(Style, string) (Style, error) {
+	return func(s Style, value string) (Style, error) {
+		i, err := parseLength(value)
+		if err != nil {
+			return s, err
+		}
+		return set(s, i), nil
+	}
+}
+
+// widths and heights can be percentages, auto fits the content
+func size(set func(Style, float32) Style) func(Style, string) (Style, error) {
+	return func(s Style, value string) (Style, error) {
+		if value == "auto" {
+			return set(s, 0), nil
+		}
+		if percent, ok := strings.CutSuffix(value, "%"); ok {
+			i, err := strconv.ParseInt(percent, 10, 32)
+			if err != nil || i < 0 {
+				return s, fmt.Errorf("invalid percentage %q", value)
+			}
+			return set(s, Percent(int32(i))), nil
+		}
+		i, err := parseLength(value)
+		if err != nil {
+			return s, err
+		}
+		return set(s, float32(i)), nil
+	}
+}
+
+// sides sets the sides of a padding or margin shorthand, with 1 to 4
+// values in the order of CSS: top, right, bottom and left
+func sides(top, right, bottom, left func(Style, int16) Style) func(Style, string) (Style, error) {
+	return func(s Style, value string) (Style, error) {
+		fields := strings.Fields(value)
+		if len(fields) > 4 {
+			return s, fmt.Errorf("expected 1 to 4 lengths, found %d", len(fields))
+		}
+		var v [4]int16
+		for i, field := range fields {
+			var err error
+			if v[i], err = parseLength(field); err != nil {
+				return s, err
+			}
+		}
+		switch len(fields) {
+		case 1:
+			v[1], v[2], v[3] = v[0], v[0], v[0]
+		case 2:
+			v[2], v[3] = v[0], v[1]
+		case 3:
+			v[3] = v[1]
+		}
+		return left(bottom(right(top(s, v[0]), v[1]), v[2]), v[3]), nil
+	}
+}
+
+func parseLength(value string) (int16, error) {
+	number := strings.TrimSuffix(value, "px")
+	i, err := strconv.ParseInt(number, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("expected a length in pixels, found %q", value)
+	}
+	return int16(i), nil
+}
+
+func colorValue(set func(Style, color.RGBA) Style) func(Style, string) (Style, error) {
+	return func(s Style, value string) (Style, error) {
+		c, err := parseColor(value)
+		if err != nil {
+			return s, err
+		}
+		return set(s, c), nil
+	}
+}
+
+var namedColors = map[string]color.RGBA{
+	"transparent": {0, 0, 0, 0},
+	"black":       {0, 0, 0, 255},
+	"white":       {255, 255, 255, 255},
+	"gray":        {128, 128, 128, 255},
+	"red":         {255, 0, 0, 255},
+	"green":       {0, 128, 0, 255},
+	"blue":        {0, 0, 255, 255},
+}
+
+func parseColor(value string) (color.RGBA, error) {
+	value = strings.ToLower(value)
+	if c, ok := namedColors[value]; ok {
+		return c, nil
+	}
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		return parseHexColor(hex)
+	}
+	for _, function := range [...]string{"rgba(", "rgb("} {
+		args, ok := strings.CutPrefix(value, function)
+		if !ok {
+			continue
+		}
+		args, ok = strings.CutSuffix(args, ")")
+		if !ok {
+			return color.RGBA{}, fmt.Errorf("expected ) after %q", value)
+		}
+		return parseRGB(args)
+	}
+	return color.RGBA{}, fmt.Errorf("invalid color %q", value)
+}
+
+// #rgb, #rgba, #rrggbb or #rrggbbaa
+func parseHexColor(hex string) (color.RGBA, error) {
+	if len(hex) == 3 || len(hex) == 4 {
+		long := make([]byte, 0, 8)
+		for i := range len(hex) {
+			long = append(long, hex[i], hex[i])
+		}
+		hex = string(long)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 8 {
+		return color.RGBA{}, fmt.Errorf("invalid color #%s", hex)
+	}
+	return color.RGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+// the arguments of rgb() and rgba(), the alpha is between 0 and 1
+func parseRGB(args string) (color.RGBA, error) {
+	parts := strings.Split(args, ",")
+	if len(parts) != 3 && len(parts) != 4 {
+		return color.RGBA{}, fmt.Errorf("expected 3 or 4 values, found %d", len(parts))
+	}
+	c := [4]uint8{3: 255}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if i == 3 {
+			a, err := strconv.ParseFloat(part, 32)
+			if err != nil || a < 0 || a > 1 {
+				return color.RGBA{}, fmt.Errorf("expected an alpha between 0 and 1, found %q", part)
+			}
+			c[3] = uint8(a*255 + 0.5)
+			continue
+		}
+		v, err := strconv.ParseUint(part, 10, 8)
+		if err != nil {
+			return color.RGBA{}, fmt.Errorf("expected a value between 0 and 255, found %q", part)
+		}
+		c[i] = uint8(v)
+	}
+	return color.RGBA{c[0], c[1], c[2], c[3]}, nil
+}
+
+var borderStyles = map[string]BorderStyle{
+	"solid":  BorderSolid,
+	"dashed": BorderDashed,
+	"dotted": BorderDotted,
+}
+
+// borderSides sets the sides, in the order of CSS shorthands, to a width,
+// a style and a color in any order, like "1px solid #fff". The style is
+// solid and the color black when they're left out.
+func borderSides(sides ...int) func(Style, string) (Style, error) {
+	return func(s Style, value string) (Style, error) {
+		side := BorderSide{Color: color.RGBA{0, 0, 0, 255}}
+		if value == "none" {
+			side.Color = color.RGBA{}
+			value = "0"
+		}
+		for _, field := range splitValues(value) {
+			if style, ok := borderStyles[field]; ok {
+				side.Style = style
+				continue
+			}
+			if width, err := parseLength(field); err == nil {
+				side.Width = max(0, width)
+				continue
+			}
+			c, err := parseColor(field)
+			if err != nil {
+				return s, fmt.Errorf("expected a width, a style or a color, found %q", field)
+			}
+			side.Color = c
+		}
+		all := s.border.sides()
+		for _, i := range sides {
+			*all[i] = side
+			s.set |= borderProperties[i].side | borderProperties[i].style
+		}
+		return s, nil
+	}
+}
+
+func borderWidths(s Style, value string) (Style, error) {
+	all := s.border.sides()
+	widths := sides(
+		func(s Style, i int16) Style { return s.BorderTop(i, all[0].Color) },
+		func(s Style, i int16) Style { return s.BorderRight(i, all[1].Color) },
+		func(s Style, i int16) Style { return s.BorderBottom(i, all[2].Color) },
+		func(s Style, i int16) Style { return s.BorderLeft(i, all[3].Color) },
+	)
+	return widths(s, value)
+}
+
+func borderColor(s Style, value string) (Style, error) {
+	c, err := parseColor(value)
+	if err != nil {
+		return s, err
+	}
+	all := s.border.sides()
+	return s.BorderTop(all[0].Width, c).
+		BorderRight(all[1].Width, c).
+		BorderBottom(all[2].Width, c).
+		BorderLeft(all[3].Width, c), nil
+}
+
+func borderStyle(s Style, value string) (Style, error) {
+	style, ok := borderStyles[strings.ToLower(value)]
+	if !ok {
+		return s, fmt.Errorf("unknown border style %q", value)
+	}
+	return s.borderStyle(style), nil
+}
+
+// splitValues splits the value at spaces, except inside parentheses
+// like the ones of rgb()
+func splitValues(value string) []string {
+	var fields []string
+	depth, start := 0, -1
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth = max(0, depth-1)
+		case isSpace(c) && depth == 0:
+			if start != -1 {
+				fields = append(fields, value[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start == -1 {
+			start = i
+		}
+	}
+	if start != -1 {
+		fields = append(fields, value[start:])
+	}
+	return fields
+}
@@ -0,0 +1,128 @@
+package gala
+
+import (
+	"errors"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestParseStyle(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	tests := []struct {
+		declarations string
+		want         Style
+	}{
+		{"", Style{}},
+		{"; ;color: red;", Style{}.TextColor(color.RGBA{255, 0, 0, 255})},
+		{"display: none; position: absolute", Style{}.Display_None().Position_Absolute()},
+		{"flex-direction: column; justify-content: space-between", Style{}.FlexDirection_Column().JustifyContent_SpaceBetween()},
+		{"align-items: center", Style{}.AlignItems_Center()},
+		{"flex: 2; z-index: -3", Style{}.Flex(2).ZIndex(-3)},
+		{"width: 50%; height: 20px", Style{}.Width(Percent(50)).Height(20)},
+		{"width: auto; height: 20", Style{}.Width(0).Height(20)},
+		{"left: 1px; top: 2px; right: 3px; bottom: 4px", Style{}.Left(1).Top(2).Right(3).Bottom(4)},
+		{"padding: 4px", Style{}.Padding(4)},
+		{"padding: 4px 8px", Style{}.PaddingTop(4).PaddingBottom(4).PaddingLeft(8).PaddingRight(8)},
+		{"margin: 1px 2px 3px", Style{}.MarginTop(1).MarginRight(2).MarginBottom(3).MarginLeft(2)},
+		{"margin: 1 2 3 4; margin-left: 9", Style{}.MarginTop(1).MarginRight(2).MarginBottom(3).MarginLeft(9)},
+		{"background: #fff", Style{}.BackgroundColor(white)},
+		{"background-color: #ffffff80", Style{}.BackgroundColor(color.RGBA{255, 255, 255, 128})},
+		{"color: rgb(1, 2, 3)", Style{}.TextColor(color.RGBA{1, 2, 3, 255})},
+		{"color: rgba(1, 2, 3, 0.5)", Style{}.TextColor(color.RGBA{1, 2, 3, 128})},
+		{"color: transparent", Style{}.TextColor(color.RGBA{})},
+		{"overflow: hidden; font-size: 20px; border-radius: 4px", Style{}.Overflow_Hidden().FontSize(20).BorderRadius(4)},
+		{"border: 2px dashed #fff", Style{}.Border(2, white).BorderStyle_Dashed()},
+		{"border-top: 1px", Style{}.BorderTop(1, color.RGBA{0, 0, 0, 255}).BorderTopStyle(BorderSolid)},
+		{"border-left: dotted 3px red; border-right: none", Style{}.BorderLeft(3, color.RGBA{255, 0, 0, 255}).
+			BorderLeftStyle(BorderDotted).BorderRight(0, color.RGBA{}).BorderRightStyle(BorderSolid)},
+		{"border: 1px white; border-width: 0 2px", Style{}.BorderTop(0, white).BorderBottom(0, white).
+			BorderLeft(2, white).BorderRight(2, white).BorderStyle_Solid()},
+	}
+	for _, test := range tests {
+		got, err := ParseStyle(test.declarations)
+		if err != nil {
+			t.Errorf("%q: %v", test.declarations, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q:\n got %q\nwant %q", test.declarations, got, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"#a { color: red", "1:16: unexpected end of stylesheet, expected }"},
+		{"#a {\n  colour: red;\n}", `2:3: unknown property "colour"`},
+		{"#a {\n  width: wide;\n}", `2:10: width: expected a length in pixels, found "wide"`},
+		{"#a\n{\n\tdisplay: grid\n}", `3:11: display: unknown value "grid"`},
+		{"#a:wiggle { color: red }", "1:3: unknown pseudo-class :wiggle"},
+		{"{ color: red }", "1:1: expected a selector before '{'"},
+		{".a, { color: red }", "1:5: expected a selector before '{'"},
+		{"#a { color red }", `1:12: expected : after "color"`},
+		{"#a { color: red } }", "1:19: unexpected '}' in selector"},
+		{"/* open comment", "1:1: unterminated comment"},
+		{"#a { padding: 1px 2px 3px 4px 5px }", "1:15: padding: expected 1 to 4 lengths, found 5"},
+		{"#a { color: #12345 }", "1:13: color: invalid color #12345"},
+		{"#a { border: 1px wavy }", `1:14: border: expected a width, a style or a color, found "wavy"`},
+	}
+	for _, test := range tests {
+		_, err := ParseStylesheet(test.src)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q: error %v, want a *ParseError", test.src, err)
+			continue
+		}
+		if err.Error() != test.want {
+			t.Errorf("%q:\n got %s\nwant %s", test.src, err, test.want)
+		}
+	}
+
+	err := &ParseError{File: "style.css", Line: 2, Column: 3, Message: "oops"}
+	if err.Error() != "style.css:2:3: oops" {
+		t.Errorf("error in a file is %q", err)
+	}
+}
+
+func TestApplyStylesheet(t *testing.T) {
+	sheet, err := ParseStylesheet(`
+		/* later and more specific rules win */
+		* { font-size: 10px }
+		.row { padding: 1px }
+		.row.wide { padding: 2px }
+		#list .row { margin: 3px }
+		#first { padding: 4px }
+		.row { padding: 5px }
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, r := newTestLayout()
+	l.UseStylesheet(sheet)
+	first := l.Box().Id("first").Class("row")
+	wide := l.Box().Class("row wide")
+	outside := l.Box().Class("row")
+	l.Box().Id("list").Contains(first, wide)
+	l.End(r)
+
+	tests := []struct {
+		name            string
+		box             *Box
+		padding, margin int16
+	}{
+		{"id over classes", first, 4, 3},
+		{"two classes over one", wide, 2, 3},
+		{"outside of the list", outside, 5, 0},
+	}
+	for _, test := range tests {
+		d := test.box.declared
+		if d.padding.top != test.padding || d.margin.top != test.margin || d.fontSize != 10 {
+			t.Errorf("%s: padding %d, margin %d, font size %d, want %d, %d, 10",
+				test.name, d.padding.top, d.margin.top, d.fontSize, test.padding, test.margin)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package gala
+
+import (
+	"image/color"
+)
+
+const defaultFontSize = 20
+
+type overflow int8
+
+const (
+	overflowVisible overflow = iota
+	overflowHidden
+)
+
+/*
+Text is drawn inside the padding of the box.
+
+If width or height are not set, the box is sized to fit the text.
+*/
+func (b *Box) Text(s string) *Box {
+	b.text = s
+	return b
+}
+
+func (b *Box) FontSize(i int16) *Box {
+	b.fontSize = max(1, i)
+	return b
+}
+
+func (b *Box) TextColor(col color.RGBA) *Box {
+	b.textColor = col
+	return b
+}
+
+// Overflow
+
+// children and text are drawn over the edges of the box
+func (b *Box) Overflow_Visible() *Box {
+	b.overflow = overflowVisible
+	return b
+}
+
+// children and text are clipped to the box
+func (b *Box) Overflow_Hidden() *Box {
+	b.overflow = overflowHidden
+	return b
+}
+
+// MeasureText returns the width of the text in pixels,
+// using the renderer passed to End. It's 0 before the first End.
+func (l *Layout) MeasureText(text string, fontSize int16) int16 {
+	if l.renderer == nil || text == "" {
+		return 0
+	}
+	return int16(l.renderer.MeasureText(text, int32(fontSize)))
+}
+
+// size text boxes that don't have a width or height
+func (l *Layout) fitText(element *Box) {
+	if element.text == "" {
+		return
+	}
+	if element.width == 0 {
+		element.width = float32(l.MeasureText(element.text, element.fontSize) +
+			element.padding.left + element.padding.right)
+	}
+	if element.height == 0 {
+		element.height = float32(element.fontSize +
+			element.padding.top + element.padding.bottom)
+	}
+}
+
+// the intersection of two rects, empty if they don't overlap
+func (r Rect) intersect(o Rect) Rect {
+	x, y := max(r.X, o.X), max(r.Y, o.Y)
+	right, bottom := min(r.Right(), o.Right()), min(r.Bottom(), o.Bottom())
+	if right < x || bottom < y {
+		return Rect{x, y, 0, 0}
+	}
+	return Rect{x, y, right - x, bottom - y}
+}
+
+// resolveClip computes the rect the box is clipped to
+// out of the clip of its parent. Parents must be resolved first.
+func (b *Box) resolveClip(root Rect) {
+	b.clip = root
+	if b.parent != nil {
+		b.clip = b.parent.clip
+	}
+	if b.overflow == overflowHidden {
+		b.clip = b.clip.intersect(b.Rect())
+	}
+}
+
+// draw draws the box with its background, border, text and custom painting.
+func (b *Box) draw(renderer Renderer) {
+	if rounded, ok := renderer.(RoundedRenderer); ok && b.radius > 0 {
+		rounded.DrawRoundedRect(int32(b.x), int32(b.y), int32(b.width), int32(b.height),
+			int32(b.radius), b.backgroundColor)
+	} else {
+		renderer.DrawRect(int32(b.x), int32(b.y), int32(b.width), int32(b.height), b.backgroundColor)
+	}
+	if b.border.visible() {
+		renderer.DrawBorder(int32(b.x), int32(b.y), int32(b.width), int32(b.height), b.border)
+	}
+	if b.text != "" {
+		renderer.DrawText(b.text,
+			int32(b.x+b.padding.left),
+			int32(b.y+b.padding.top),
+			int32(b.fontSize), b.textColor)
+	}
+	if b.paint != nil {
+		b.paint(b, renderer)
+	}
+}
@@ -0,0 +1,288 @@
+package gala
+
+import "strings"
+
+// a line of a text area after wrapping, end is exclusive and
+// doesn't include the line break.
+type textLine struct {
+	start, end int
+}
+
+type textAreaState struct {
+	textEditor
+	scroll int16 // how far the text is scrolled up
+
+	// the column kept when moving up and down
+	preferredX    int16
+	hasPreferredX bool
+
+	lines     []textLine
+	wrapped   string // the text the lines were wrapped for
+	wrapWidth int16
+}
+
+// the distance between two lines of text
+func lineHeight(fontSize int16) int16 {
+	return fontSize + fontSize/4
+}
+
+/*
+TextArea is a multi-line text field that edits text.
+
+Lines are wrapped to the width of the box and it scrolls to keep the caret
+in view. Its caret, selection, scroll and undo history are kept per id.
+By default it's 300 by 150 pixels.
+
+	Up, Down, PageUp, PageDown: move by line, keeping the column
+	Home, End: move to the start or end of the line, or the text while holding control
+*/
+func (l *Layout) TextArea(id string, text *string) *Box {
+	box := l.Box().
+		Id(id).
+		Focusable().
+		Overflow_Hidden().
+		Padding(4).
+		Size(300, 150).
+		Scrollable()
+	box.update = func(box *Box) {
+		l.scrollTextArea(box, *text)
+	}
+	box.onInput = func(box *Box) {
+		l.editTextArea(box, text)
+	}
+	box.paint = func(box *Box, renderer Renderer) {
+		l.paintTextArea(box, renderer, *text)
+	}
+	return box
+}
+
+// wraps the text again if it or the width of the box changed
+func (l *Layout) wrapTextArea(s *textAreaState, box *Box, text string) {
+	width := box.contentRect().Width
+	if s.lines != nil && s.wrapped == text && s.wrapWidth == width {
+		return
+	}
+	s.wrapped, s.wrapWidth = text, width
+	s.lines = s.lines[:0]
+	start := 0
+	for {
+		end := strings.IndexByte(text[start:], '\n')
+		if end == -1 {
+			s.lines = l.wrapLine(s.lines, text, start, len(text), box.fontSize, width)
+			return
+		}
+		s.lines = l.wrapLine(s.lines, text, start, start+end, box.fontSize, width)
+		start += end + 1
+	}
+}
+
+// wrapLine splits text[start:end] into lines that fit the width,
+// breaking after spaces when possible.
+func (l *Layout) wrapLine(lines []textLine, text string, start, end int, fontSize, width int16) []textLine {
+	for {
+		if l.MeasureText(text[start:end], fontSize) <= width {
+			return append(lines, textLine{start, end})
+		}
+		fits, afterSpace := start, -1
+		for i := start; i < end; i = nextRune(text, i) {
+			next := nextRune(text, i)
+			if l.MeasureText(text[start:next], fontSize) > width {
+				break
+			}
+			fits = next
+			if isSpaceAt(text, i) {
+				afterSpace = next
+			}
+		}
+		if afterSpace > start {
+			fits = afterSpace
+		}
+		if fits == start {
+			// not even one character fits, use it anyway
+			fits = nextRune(text, start)
+		}
+		lines = append(lines, textLine{start, fits})
+		if fits == end {
+			// the last character was broken off on its own
+			return lines
+		}
+		start = fits
+	}
+}
+
+// lineOf returns the line the position is on. A position at the end of a
+// wrapped line is at the start of the next one.
+func lineOf(lines []textLine, pos int) int {
+	for i, line := range lines {
+		if pos < line.end {
+			return i
+		}
+		if pos == line.end && (i == len(lines)-1 || lines[i+1].start > pos) {
+			return i
+		}
+	}
+	return len(lines) - 1
+}
+
+// the position on the line closest to x
+func (l *Layout) caretOnLine(lines []textLine, i int, text string, fontSize, x int16) int {
+	line := lines[i]
+	pos := line.start + l.caretAt(text[line.start:line.end], fontSize, x)
+	// the end of a wrapped line would put the caret on the next line
+	if pos == line.end && i < len(lines)-1 && lines[i+1].start == line.end {
+		pos = prevRune(text, pos)
+	}
+	return pos
+}
+
+// the position closest to the point, relative to the content of the box
+func (l *Layout) caretAtPoint(s *textAreaState, box *Box, text string, x, y int16) int {
+	i := int((y + s.scroll) / lineHeight(box.fontSize))
+	i = min(max(0, i), len(s.lines)-1)
+	return l.caretOnLine(s.lines, i, text, box.fontSize, x)
+}
+
+// moves the caret by a number of lines, keeping the preferred column
+func (l *Layout) moveLines(s *textAreaState, text string, fontSize int16, delta int, extend bool) {
+	current := lineOf(s.lines, s.caret)
+	if !s.hasPreferredX {
+		s.preferredX = l.MeasureText(text[s.lines[current].start:s.caret], fontSize)
+	}
+	preferredX := s.preferredX
+	target := current + delta
+	switch {
+	case target < 0:
+		s.moveTo(0, extend)
+	case target >= len(s.lines):
+		s.moveTo(len(text), extend)
+	default:
+		s.moveTo(l.caretOnLine(s.lines, target, text, fontSize, preferredX), extend)
+	}
+	// moveTo doesn't know about columns
+	s.preferredX, s.hasPreferredX = preferredX, true
+}
+
+// scrolls the text area with the mouse wheel while it's hovered
+func (l *Layout) scrollTextArea(box *Box, text string) {
+	s := State[textAreaState](l, box.hash)
+	l.wrapTextArea(s, box, text)
+	s.scroll -= int16(l.ConsumeWheel(box.hash) * 3 * float32(lineHeight(box.fontSize)))
+	s.scroll = l.clampTextAreaScroll(s, box)
+}
+
+func (l *Layout) clampTextAreaScroll(s *textAreaState, box *Box) int16 {
+	contentHeight := int16(len(s.lines)) * lineHeight(box.fontSize)
+	return max(0, min(s.scroll, contentHeight-box.contentRect().Height))
+}
+
+// editTextArea handles the mouse and keyboard while the text area has focus
+func (l *Layout) editTextArea(box *Box, text *string) {
+	s := State[textAreaState](l, box.hash)
+	s.clamp(*text)
+	l.wrapTextArea(s, box, *text)
+	content := box.contentRect()
+	caret, before := s.caret, *text
+
+	mouseX, mouseY := l.MousePos()
+	x, y := int16(mouseX)-content.X, int16(mouseY)-content.Y
+	if l.MousePressed(MouseLeft) && box.Rect().Contains(mouseX, mouseY) {
+		s.moveTo(l.caretAtPoint(s, box, *text, x, y), l.ShiftDown())
+		s.hasPreferredX = false
+		s.dragging = true
+	} else if s.dragging && l.MouseDown(MouseLeft) {
+		s.caret = l.caretAtPoint(s, box, *text, x, y)
+	} else {
+		s.dragging = false
+	}
+
+	shift, control := l.ShiftDown(), l.ControlDown()
+	page := max(1, int(content.Height/lineHeight(box.fontSize)))
+	l.consumeKeys(func(key Key) bool {
+		// lines depend on the text, which the previous key may have changed
+		l.wrapTextArea(s, box, *text)
+		line := s.lines[lineOf(s.lines, s.caret)]
+		switch {
+		case key == KeyUp:
+			l.moveLines(s, *text, box.fontSize, -1, shift)
+		case key == KeyDown:
+			l.moveLines(s, *text, box.fontSize, 1, shift)
+		case key == KeyPageUp:
+			l.moveLines(s, *text, box.fontSize, -page, shift)
+		case key == KeyPageDown:
+			l.moveLines(s, *text, box.fontSize, page, shift)
+		case key == KeyHome && !control:
+			s.moveTo(line.start, shift)
+			s.hasPreferredX = false
+		case key == KeyEnd && !control:
+			end := line.end
+			if i := lineOf(s.lines, s.caret); i < len(s.lines)-1 && s.lines[i+1].start == end {
+				end = prevRune(*text, end)
+			}
+			s.moveTo(end, shift)
+			s.hasPreferredX = false
+		case key == KeyEnter:
+			s.replaceSelection(text, "\n", editTyping)
+			s.hasPreferredX = false
+		default:
+			if !s.editKey(l, text, key) {
+				return false
+			}
+			s.hasPreferredX = false
+		}
+		return true
+	})
+	if !control && len(l.chars) > 0 {
+		s.typeChars(text, l.chars)
+		l.chars = l.chars[:0]
+		s.hasPreferredX = false
+	}
+
+	// keep the caret in view when it moved, so the wheel can scroll away from it
+	l.wrapTextArea(s, box, *text)
+	if s.caret == caret && *text == before {
+		return
+	}
+	caretY := int16(lineOf(s.lines, s.caret)) * lineHeight(box.fontSize)
+	if caretY < s.scroll {
+		s.scroll = caretY
+	}
+	if caretY+lineHeight(box.fontSize) > s.scroll+content.Height {
+		s.scroll = caretY + lineHeight(box.fontSize) - content.Height
+	}
+	s.scroll = l.clampTextAreaScroll(s, box)
+}
+
+func (l *Layout) paintTextArea(box *Box, renderer Renderer, text string) {
+	s := State[textAreaState](l, box.hash)
+	s.clamp(text)
+	l.wrapTextArea(s, box, text)
+	content := box.contentRect()
+	fontSize := int32(box.fontSize)
+	height := lineHeight(box.fontSize)
+	focused := l.Focused(box.hash)
+	start, end := s.selection()
+	caretLine := lineOf(s.lines, s.caret)
+
+	for i := int(s.scroll / height); i < len(s.lines); i++ {
+		line := s.lines[i]
+		y := int32(content.Y + int16(i)*height - s.scroll)
+		if y > int32(content.Bottom()) {
+			break
+		}
+		x := int32(content.X)
+		if focused && start < line.end && end > line.start {
+			from := int32(l.MeasureText(text[line.start:max(start, line.start)], box.fontSize))
+			to := int32(l.MeasureText(text[line.start:min(end, line.end)], box.fontSize))
+			if end > line.end {
+				// include the line break
+				to += fontSize / 4
+			}
+			renderer.DrawRect(x+from, y, to-from, int32(height), selectionColor)
+		}
+		renderer.DrawText(text[line.start:line.end], x, y, fontSize, box.textColor)
+		if focused && i == caretLine {
+			caretX := int32(l.MeasureText(text[line.start:s.caret], box.fontSize))
+			renderer.DrawRect(x+caretX, y, 1, fontSize, box.textColor)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package gala
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrapLine(t *testing.T) {
+	l, _ := newTestLayout()
+	// 10 pixels per character at font size 20, so 10 characters fit in 100
+	tests := []struct {
+		name  string
+		text  string
+		width int16
+		lines []string
+	}{
+		{"fits", "short", 100, []string{"short"}},
+		{"empty", "", 100, []string{""}},
+		{"exactly fits", "0123456789", 100, []string{"0123456789"}},
+		{"after spaces", "one two three four", 100, []string{"one two ", "three four"}},
+		{"long word", "abcdefghijklmnop", 100, []string{"abcdefghij", "klmnop"}},
+		{"word after space", "a bcdefghijklmn", 100, []string{"a ", "bcdefghijk", "lmn"}},
+		{"narrower than a character", "abc", 5, []string{"a", "b", "c"}},
+		{"runes", "ééééééééééé", 100, []string{"éééééééééé", "é"}},
+	}
+	for _, test := range tests {
+		var lines []string
+		for _, line := range l.wrapLine(nil, test.text, 0, len(test.text), 20, test.width) {
+			lines = append(lines, test.text[line.start:line.end])
+		}
+		if !reflect.DeepEqual(lines, test.lines) {
+			t.Errorf("%s: wrapLine(%q) = %q, want %q", test.name, test.text, lines, test.lines)
+		}
+	}
+}
+
+func TestLineOf(t *testing.T) {
+	// "one two " wrapped from "three", then a line break, then "four"
+	lines := []textLine{{0, 8}, {8, 13}, {14, 18}}
+	tests := []struct{ pos, line int }{
+		{0, 0}, {7, 0}, {8, 1}, {13, 1}, {14, 2}, {18, 2},
+	}
+	for _, test := range tests {
+		if got := lineOf(lines, test.pos); got != test.line {
+			t.Errorf("lineOf(%d) = %d, want %d", test.pos, got, test.line)
+		}
+	}
+}
+
+func TestUndo(t *testing.T) {
+	var e textEditor
+	text := ""
+	e.typeChars(&text, []rune("hello"))
+	e.typeChars(&text, []rune(" world"))
+	e.anchor = prevWord(text, e.caret)
+	e.replaceSelection(&text, "", editDeleting)
+	if text != "hello " {
+		t.Fatalf("text = %q, want %q", text, "hello ")
+	}
+
+	steps := []struct {
+		redo  bool
+		text  string
+		caret int
+	}{
+		// consecutive typing is undone at once
+		{false, "hello world", 11},
+		{false, "", 0},
+		// nothing left to undo
+		{false, "", 0},
+		{true, "hello world", 11},
+		{true, "hello ", 6},
+		{true, "hello ", 6},
+	}
+	for i, step := range steps {
+		if step.redo {
+			e.restore(&text, &e.redo, &e.undo)
+		} else {
+			e.restore(&text, &e.undo, &e.redo)
+		}
+		if text != step.text || e.caret != step.caret {
+			t.Errorf("step %d: text %q, caret %d, want %q, %d", i, text, e.caret, step.text, step.caret)
+		}
+	}
+}
+
+func TestUndoClearsRedo(t *testing.T) {
+	var e textEditor
+	text := ""
+	e.typeChars(&text, []rune("ab"))
+	e.restore(&text, &e.undo, &e.redo)
+	e.typeChars(&text, []rune("c"))
+	e.restore(&text, &e.redo, &e.undo)
+	if text != "c" {
+		t.Errorf("text = %q after redo, want %q, a new edit drops what was undone", text, "c")
+	}
+}
+
+func TestUndoLimit(t *testing.T) {
+	var e textEditor
+	text := ""
+	for range maxUndo + 10 {
+		e.replaceSelection(&text, "x", editOther)
+	}
+	if len(e.undo) != maxUndo {
+		t.Errorf("%d snapshots kept, want %d", len(e.undo), maxUndo)
+	}
+}
@@ -0,0 +1,286 @@
+package gala
+
+import (
+	"image/color"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var selectionColor = color.RGBA{51, 153, 255, 110}
+
+const maxUndo = 100
+
+type editKind int8
+
+const (
+	editNone editKind = iota
+	editTyping
+	editDeleting
+	editOther
+)
+
+type textSnapshot struct {
+	text          string
+	caret, anchor int
+}
+
+// textEditor holds the caret, selection and undo history shared by the text widgets.
+// Positions are byte offsets into the text.
+type textEditor struct {
+	caret, anchor int // the selection is between them
+	dragging      bool
+
+	undo, redo []textSnapshot
+	lastEdit   editKind // consecutive typing or deleting is undone at once
+
+	singleLine bool // line breaks pasted into it become spaces
+}
+
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// keep the caret and anchor inside the text, the text may have changed
+// since the last frame.
+func (e *textEditor) clamp(text string) {
+	e.caret = runeStart(text, min(max(0, e.caret), len(text)))
+	e.anchor = runeStart(text, min(max(0, e.anchor), len(text)))
+}
+
+func (e *textEditor) selection() (start, end int) {
+	return min(e.caret, e.anchor), max(e.caret, e.anchor)
+}
+
+func (e *textEditor) hasSelection() bool {
+	return e.caret != e.anchor
+}
+
+// moves the caret, extend keeps the anchor where it is to grow the selection
+func (e *textEditor) moveTo(pos int, extend bool) {
+	e.caret = pos
+	if !extend {
+		e.anchor = pos
+	}
+	e.lastEdit = editNone
+}
+
+// replaces the selection (or inserts at the caret) and moves the caret after it
+func (e *textEditor) replaceSelection(text *string, with string, kind editKind) {
+	start, end := e.selection()
+	if start == end && with == "" {
+		return
+	}
+	if kind == editOther || kind != e.lastEdit {
+		e.undo = append(e.undo, textSnapshot{*text, e.caret, e.anchor})
+		if len(e.undo) > maxUndo {
+			e.undo = e.undo[1:]
+		}
+		e.redo = e.redo[:0]
+	}
+	e.lastEdit = kind
+	*text = (*text)[:start] + with + (*text)[end:]
+	e.caret = start + len(with)
+	e.anchor = e.caret
+}
+
+// restores the last snapshot of from, saving the current text to to
+func (e *textEditor) restore(text *string, from, to *[]textSnapshot) {
+	if len(*from) == 0 {
+		return
+	}
+	last := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, textSnapshot{*text, e.caret, e.anchor})
+	*text, e.caret, e.anchor = last.text, last.caret, last.anchor
+	e.lastEdit = editNone
+}
+
+// typeChars inserts the characters typed this frame
+func (e *textEditor) typeChars(text *string, chars []rune) {
+	for _, char := range chars {
+		if !unicode.IsPrint(char) {
+			continue
+		}
+		e.replaceSelection(text, string(char), editTyping)
+	}
+}
+
+/*
+editKey handles the keys every text widget has in common.
+It returns false for keys it doesn't use.
+
+	Left, Right: move by character, or by word while holding control
+	Home, End: move to the start or end of the text
+	Shift: extends the selection with any of the above
+	Backspace, Delete: delete a character, or a word while holding control
+	Control + A, C, X, V: select all, copy, cut and paste, pasting
+	into a single line turns its line breaks into spaces
+	Control + Z, Y (or Shift + Z): undo and redo
+*/
+func (e *textEditor) editKey(l *Layout, text *string, key Key) bool {
+	shift, control := l.ShiftDown(), l.ControlDown()
+	switch key {
+	case KeyLeft:
+		switch {
+		case e.hasSelection() && !shift:
+			start, _ := e.selection()
+			e.moveTo(start, false)
+		case control:
+			e.moveTo(prevWord(*text, e.caret), shift)
+		default:
+			e.moveTo(prevRune(*text, e.caret), shift)
+		}
+	case KeyRight:
+		switch {
+		case e.hasSelection() && !shift:
+			_, end := e.selection()
+			e.moveTo(end, false)
+		case control:
+			e.moveTo(nextWord(*text, e.caret), shift)
+		default:
+			e.moveTo(nextRune(*text, e.caret), shift)
+		}
+	case KeyHome:
+		e.moveTo(0, shift)
+	case KeyEnd:
+		e.moveTo(len(*text), shift)
+	case KeyBackspace:
+		if !e.hasSelection() {
+			if control {
+				e.anchor = prevWord(*text, e.caret)
+			} else {
+				e.anchor = prevRune(*text, e.caret)
+			}
+		}
+		e.replaceSelection(text, "", editDeleting)
+	case KeyDelete:
+		if !e.hasSelection() {
+			if control {
+				e.anchor = nextWord(*text, e.caret)
+			} else {
+				e.anchor = nextRune(*text, e.caret)
+			}
+		}
+		e.replaceSelection(text, "", editDeleting)
+	case KeyA:
+		if !control {
+			return false
+		}
+		e.anchor, e.caret = 0, len(*text)
+		e.lastEdit = editNone
+	case KeyC, KeyX:
+		if !control {
+			return false
+		}
+		start, end := e.selection()
+		if l.clipboard != nil && start != end {
+			l.clipboard.SetClipboardText((*text)[start:end])
+		}
+		if key == KeyX {
+			e.replaceSelection(text, "", editOther)
+		}
+	case KeyV:
+		if !control {
+			return false
+		}
+		if l.clipboard != nil {
+			pasted := l.clipboard.ClipboardText()
+			if e.singleLine {
+				pasted = lineBreaks.Replace(pasted)
+			}
+			e.replaceSelection(text, pasted, editOther)
+		}
+	case KeyZ:
+		if !control {
+			return false
+		}
+		if shift {
+			e.restore(text, &e.redo, &e.undo)
+		} else {
+			e.restore(text, &e.undo, &e.redo)
+		}
+	case KeyY:
+		if !control {
+			return false
+		}
+		e.restore(text, &e.redo, &e.undo)
+	default:
+		return false
+	}
+	return true
+}
+
+// caretAt returns the position in the text closest to x,
+// x is relative to the start of the text.
+func (l *Layout) caretAt(text string, fontSize int16, x int16) int {
+	best, bestDistance := 0, abs(x)
+	for i := range text {
+		if i == 0 {
+			continue
+		}
+		if d := abs(l.MeasureText(text[:i], fontSize) - x); d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	if d := abs(l.MeasureText(text, fontSize) - x); d < bestDistance {
+		best = len(text)
+	}
+	return best
+}
+
+func abs(i int16) int16 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// the start of the rune i is in
+func runeStart(text string, i int) int {
+	for i > 0 && i < len(text) && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
+
+func prevRune(text string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	_, size := utf8.DecodeLastRuneInString(text[:i])
+	return i - size
+}
+
+func nextRune(text string, i int) int {
+	if i >= len(text) {
+		return len(text)
+	}
+	_, size := utf8.DecodeRuneInString(text[i:])
+	return i + size
+}
+
+func isSpaceAt(text string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return unicode.IsSpace(r)
+}
+
+// the start of the word before i
+func prevWord(text string, i int) int {
+	for i > 0 && isSpaceAt(text, prevRune(text, i)) {
+		i = prevRune(text, i)
+	}
+	for i > 0 && !isSpaceAt(text, prevRune(text, i)) {
+		i = prevRune(text, i)
+	}
+	return i
+}
+
+// the start of the word after i
+func nextWord(text string, i int) int {
+	for i < len(text) && !isSpaceAt(text, i) {
+		i = nextRune(text, i)
+	}
+	for i < len(text) && isSpaceAt(text, i) {
+		i = nextRune(text, i)
+	}
+	return i
+}
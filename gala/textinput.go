@@ -0,0 +1,96 @@
+package gala
+
+type textInputState struct {
+	textEditor
+	scroll int16 // how far the text is scrolled to the left
+}
+
+/*
+TextInput is a single line text field that edits text.
+
+Its caret, selection and scroll are kept per id. The box is returned
+so it can be styled like any other, by default it's 200 pixels wide
+and fits one line of text. Set the height when changing the font size.
+
+	layout.TextInput("name", &name, "Your name").Width(gala.Percent(50))
+*/
+func (l *Layout) TextInput(id string, text *string, placeholder string) *Box {
+	box := l.Box().
+		Id(id).
+		Focusable().
+		Overflow_Hidden().
+		Padding(4).
+		Size(200, defaultFontSize+8)
+	box.onInput = func(box *Box) {
+		l.editTextInput(box, text)
+	}
+	box.paint = func(box *Box, renderer Renderer) {
+		l.paintTextInput(box, renderer, *text, placeholder)
+	}
+	return box
+}
+
+// editTextInput handles the mouse and keyboard while the input has focus
+func (l *Layout) editTextInput(box *Box, text *string) {
+	s := State[textInputState](l, box.hash)
+	s.clamp(*text)
+	s.singleLine = true
+	content := box.contentRect()
+
+	mouseX, mouseY := l.MousePos()
+	if l.MousePressed(MouseLeft) && box.Rect().Contains(mouseX, mouseY) {
+		x := int16(mouseX) - content.X + s.scroll
+		s.moveTo(l.caretAt(*text, box.fontSize, x), l.ShiftDown())
+		s.dragging = true
+	} else if s.dragging && l.MouseDown(MouseLeft) {
+		x := int16(mouseX) - content.X + s.scroll
+		s.caret = l.caretAt(*text, box.fontSize, x)
+	} else {
+		s.dragging = false
+	}
+
+	l.consumeKeys(func(key Key) bool {
+		return s.editKey(l, text, key)
+	})
+	if !l.ControlDown() {
+		s.typeChars(text, l.chars)
+		l.chars = l.chars[:0]
+	}
+
+	// keep the caret in view, without scrolling past the end of the text
+	caretX := l.MeasureText((*text)[:s.caret], box.fontSize)
+	if caretX-s.scroll > content.Width-1 {
+		s.scroll = caretX - content.Width + 1
+	}
+	if caretX < s.scroll {
+		s.scroll = caretX
+	}
+	textWidth := l.MeasureText(*text, box.fontSize)
+	s.scroll = max(0, min(s.scroll, textWidth-content.Width+1))
+}
+
+func (l *Layout) paintTextInput(box *Box, renderer Renderer, text, placeholder string) {
+	s := State[textInputState](l, box.hash)
+	s.clamp(text)
+	content := box.contentRect()
+	x, y := int32(content.X-s.scroll), int32(content.Y)
+	fontSize := int32(box.fontSize)
+
+	if text == "" {
+		dimmed := box.textColor
+		dimmed.A /= 2
+		renderer.DrawText(placeholder, int32(content.X), y, fontSize, dimmed)
+	}
+	focused := l.Focused(box.hash)
+	if focused && s.hasSelection() {
+		start, end := s.selection()
+		startX := int32(l.MeasureText(text[:start], box.fontSize))
+		endX := int32(l.MeasureText(text[:end], box.fontSize))
+		renderer.DrawRect(x+startX, y, endX-startX, fontSize, selectionColor)
+	}
+	renderer.DrawText(text, x, y, fontSize, box.textColor)
+	if focused {
+		caretX := int32(l.MeasureText(text[:s.caret], box.fontSize))
+		renderer.DrawRect(x+caretX, y, 1, fontSize, box.textColor)
+	}
+}
@@ -0,0 +1,85 @@
+package gala
+
+import "testing"
+
+type testClipboard struct {
+	text string
+}
+
+func (c *testClipboard) ClipboardText() string        { return c.text }
+func (c *testClipboard) SetClipboardText(text string) { c.text = text }
+
+// a frame of input, the keys in down are held while the keys are pressed
+type inputStep struct {
+	down  []Key
+	keys  []Key
+	chars string
+}
+
+func TestTextInputEditing(t *testing.T) {
+	control := []Key{KeyLeftControl}
+	shift := []Key{KeyLeftShift}
+	tests := []struct {
+		name      string
+		text      string
+		clipboard string
+		steps     []inputStep
+		want      string
+		caret     int
+		copied    string // what the clipboard has afterwards
+	}{
+		{"typing", "", "", []inputStep{{chars: "héllo"}}, "héllo", 6, ""},
+		{"typing at the caret", "ac", "", []inputStep{{keys: []Key{KeyRight}, chars: "b"}}, "abc", 2, ""},
+		{"backspace and delete", "abcd", "", []inputStep{{keys: []Key{KeyRight, KeyRight, KeyBackspace, KeyDelete}}}, "ad", 1, ""},
+		{"control backspace deletes a word", "hello world", "", []inputStep{
+			{keys: []Key{KeyEnd}},
+			{down: control, keys: []Key{KeyBackspace}},
+		}, "hello ", 6, ""},
+		{"selecting replaces", "hello world", "", []inputStep{
+			{down: shift, keys: []Key{KeyEnd}},
+			{chars: "bye"},
+		}, "bye", 3, ""},
+		{"control characters aren't typed", "", "", []inputStep{{chars: "a\nb\t"}}, "ab", 2, ""},
+		{"copy", "hello", "", []inputStep{{down: control, keys: []Key{KeyA, KeyC}}}, "hello", 5, "hello"},
+		{"cut", "hello", "", []inputStep{{down: control, keys: []Key{KeyA, KeyX}}}, "", 0, "hello"},
+		{"paste", "[]", "one", []inputStep{
+			{keys: []Key{KeyRight}},
+			{down: control, keys: []Key{KeyV}},
+		}, "[one]", 4, "one"},
+		{"paste turns line breaks into spaces", "", "one\ntwo\r\nthree\r", []inputStep{
+			{down: control, keys: []Key{KeyV}},
+		}, "one two three ", 14, "one\ntwo\r\nthree\r"},
+		{"undo and redo", "", "", []inputStep{
+			{chars: "abc"},
+			{keys: []Key{KeyLeft}},
+			{chars: "x"},
+			{down: control, keys: []Key{KeyZ}},
+			{down: control, keys: []Key{KeyZ}},
+			{down: control, keys: []Key{KeyY}},
+		}, "abc", 2, ""},
+	}
+	for _, test := range tests {
+		l, r := newTestLayout()
+		in := &testInput{}
+		clipboard := &testClipboard{test.clipboard}
+		l.UseInput(in)
+		l.UseClipboard(clipboard)
+		text := test.text
+		input := l.TextInput("input", &text, "")
+		l.End(r)
+		l.Focus(input.ID())
+
+		for _, step := range append([]inputStep{{}}, test.steps...) {
+			in.down, in.keys, in.chars = step.down, step.keys, []rune(step.chars)
+			l.TextInput("input", &text, "")
+			l.End(r)
+		}
+		caret := State[textInputState](l, input.ID()).caret
+		if text != test.want || caret != test.caret {
+			t.Errorf("%s: text %q with the caret at %d, want %q at %d", test.name, text, caret, test.want, test.caret)
+		}
+		if clipboard.text != test.copied {
+			t.Errorf("%s: clipboard %q, want %q", test.name, clipboard.text, test.copied)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package gala
+
+import (
+	"image/color"
+	"maps"
+)
+
+/*
+Theme holds named tokens boxes can reference instead of values, like
+BackgroundToken("surface"). Tokens are resolved during End with the
+theme the layout uses at that time, so swapping it with UseTheme between
+frames restyles everything that references tokens.
+
+Spacing is used for padding and margins, Fonts holds font sizes and
+Radii are corner radii for widgets and renderers that draw them.
+*/
+type Theme struct {
+	Name    string
+	Colors  map[string]color.RGBA
+	Spacing map[string]int16
+	Radii   map[string]int16
+	Fonts   map[string]int16
+}
+
+// the sizes both themes start with, every Theme gets its own copy
+var (
+	themeSpacing = map[string]int16{"none": 0, "small": 4, "medium": 8, "large": 16}
+	themeRadii   = map[string]int16{"none": 0, "small": 2, "medium": 4, "large": 8}
+	themeFonts   = map[string]int16{"small": 14, "body": 18, "title": 24}
+)
+
+// DarkTheme returns a new dark theme, changing its tokens
+// doesn't change the ones of other themes.
+func DarkTheme() Theme {
+	return Theme{
+		Name: "dark",
+		Colors: map[string]color.RGBA{
+			"background":     {26, 26, 29, 255},
+			"surface":        {52, 52, 58, 255},
+			"surfaceHovered": {66, 66, 74, 255},
+			"surfacePressed": {40, 40, 45, 255},
+			"accent":         {88, 101, 242, 255},
+			"knob":           {240, 240, 245, 255},
+			"focusRing":      {140, 150, 255, 255},
+			"text":           {230, 230, 235, 255},
+			"textMuted":      {150, 150, 160, 255},
+		},
+		Spacing: maps.Clone(themeSpacing),
+		Radii:   maps.Clone(themeRadii),
+		Fonts:   maps.Clone(themeFonts),
+	}
+}
+
+// LightTheme returns a new light theme, changing its tokens
+// doesn't change the ones of other themes.
+func LightTheme() Theme {
+	return Theme{
+		Name: "light",
+		Colors: map[string]color.RGBA{
+			"background":     {245, 245, 247, 255},
+			"surface":        {225, 225, 230, 255},
+			"surfaceHovered": {210, 210, 218, 255},
+			"surfacePressed": {195, 195, 205, 255},
+			"accent":         {88, 101, 242, 255},
+			"knob":           {255, 255, 255, 255},
+			"focusRing":      {60, 75, 220, 255},
+			"text":           {30, 30, 35, 255},
+			"textMuted":      {100, 100, 110, 255},
+		},
+		Spacing: maps.Clone(themeSpacing),
+		Radii:   maps.Clone(themeRadii),
+		Fonts:   maps.Clone(themeFonts),
+	}
+}
+
+// UseTheme sets the theme tokens are resolved with, from the next End on.
+func (l *Layout) UseTheme(theme Theme) {
+	l.theme = theme
+}
+
+// Theme returns the theme the layout resolves tokens with.
+func (l *Layout) Theme() Theme {
+	return l.theme
+}
+
+// the properties a token can set
+type tokenProperty int8
+
+const (
+	tokenBackground tokenProperty = iota
+	tokenTextColor
+	tokenPadding
+	tokenMargin
+	tokenFontSize
+	tokenBorderRadius
+)
+
+type tokenRef struct {
+	box      *Box
+	property tokenProperty
+	name     string
+}
+
+// BackgroundToken sets the background color to the color token of the theme.
+func (b *Box) BackgroundToken(name string) *Box {
+	return b.token(tokenBackground, name)
+}
+
+// TextColorToken sets the text color to the color token of the theme.
+func (b *Box) TextColorToken(name string) *Box {
+	return b.token(tokenTextColor, name)
+}
+
+// PaddingToken sets the padding of all sides to the spacing token of the theme.
+func (b *Box) PaddingToken(name string) *Box {
+	return b.token(tokenPadding, name)
+}
+
+// MarginToken sets the margin of all sides to the spacing token of the theme.
+func (b *Box) MarginToken(name string) *Box {
+	return b.token(tokenMargin, name)
+}
+
+// FontSizeToken sets the font size to the font token of the theme.
+func (b *Box) FontSizeToken(name string) *Box {
+	return b.token(tokenFontSize, name)
+}
+
+// BorderRadiusToken sets the border radius to the radius token of the theme.
+func (b *Box) BorderRadiusToken(name string) *Box {
+	return b.token(tokenBorderRadius, name)
+}
+
+func (b *Box) token(property tokenProperty, name string) *Box {
+	if b.layout != nil {
+		b.layout.tokens = append(b.layout.tokens, tokenRef{b, property, name})
+	}
+	return b
+}
+
+/*
+resolveTokens sets the properties that reference tokens, over the values
+set on the boxes. Tokens the theme doesn't have leave the property as it
+was. It runs before the style variants, so they can override tokens.
+*/
+func (l *Layout) resolveTokens() {
+	t := &l.theme
+	for _, ref := range l.tokens {
+		b := ref.box
+		switch ref.property {
+		case tokenBackground:
+			if c, ok := t.Colors[ref.name]; ok {
+				b.BackgroundColor(c)
+			}
+		case tokenTextColor:
+			if c, ok := t.Colors[ref.name]; ok {
+				b.TextColor(c)
+			}
+		case tokenPadding:
+			if i, ok := t.Spacing[ref.name]; ok {
+				b.Padding(i)
+			}
+		case tokenMargin:
+			if i, ok := t.Spacing[ref.name]; ok {
+				b.Margin(i)
+			}
+		case tokenFontSize:
+			if i, ok := t.Fonts[ref.name]; ok {
+				b.FontSize(i)
+			}
+		case tokenBorderRadius:
+			if i, ok := t.Radii[ref.name]; ok {
+				b.BorderRadius(i)
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package gala
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestThemesDontShareTokens(t *testing.T) {
+	dark := DarkTheme()
+	dark.Colors["surface"] = color.RGBA{1, 2, 3, 255}
+	for _, name := range [...]string{"small", "medium"} {
+		dark.Spacing[name] += 100
+		dark.Radii[name] += 100
+	}
+	dark.Fonts["small"] += 100
+	for _, theme := range [...]Theme{DarkTheme(), LightTheme()} {
+		if theme.Colors["surface"] == dark.Colors["surface"] {
+			t.Errorf("changing the colors of a dark theme changed the %s theme", theme.Name)
+		}
+		if theme.Spacing["small"] == dark.Spacing["small"] || theme.Radii["medium"] == dark.Radii["medium"] {
+			t.Errorf("changing the sizes of a dark theme changed the %s theme", theme.Name)
+		}
+		if theme.Fonts["small"] == dark.Fonts["small"] {
+			t.Errorf("changing the fonts of a dark theme changed the %s theme", theme.Name)
+		}
+	}
+}
+
+func TestResolveTokens(t *testing.T) {
+	theme := Theme{
+		Name:    "test",
+		Colors:  DarkTheme().Colors,
+		Spacing: map[string]int16{"small": 3},
+		Radii:   map[string]int16{"round": 6},
+		Fonts:   map[string]int16{"title": 30},
+	}
+	l, r := newTestLayout()
+	l.UseTheme(theme)
+	box := l.Box().
+		BackgroundToken("surface").
+		TextColorToken("text").
+		PaddingToken("small").
+		MarginToken("small").
+		FontSizeToken("title").
+		BorderRadiusToken("round")
+	missing := l.Box().BorderRadius(2).BorderRadiusToken("missing")
+	l.End(r)
+
+	if box.backgroundColor != theme.Colors["surface"] || box.textColor != theme.Colors["text"] {
+		t.Errorf("colors %v and %v, want the surface and text tokens", box.backgroundColor, box.textColor)
+	}
+	if box.declared.padding.left != 3 || box.margin.top != 3 {
+		t.Errorf("padding %d and margin %d, want 3", box.declared.padding.left, box.margin.top)
+	}
+	if box.fontSize != 30 || box.radius != 6 {
+		t.Errorf("font size %d and radius %d, want 30 and 6", box.fontSize, box.radius)
+	}
+	if missing.radius != 2 {
+		t.Errorf("a missing token changed the radius to %d", missing.radius)
+	}
+}
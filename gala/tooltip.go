@@ -0,0 +1,30 @@
+package gala
+
+import "time"
+
+// TooltipDelay sets how long a box has to be hovered before its tooltip shows.
+func (l *Layout) TooltipDelay(delay time.Duration) {
+	l.tooltipDelay = delay
+}
+
+/*
+Tooltip shows the box returned by build next to this one,
+once it has been hovered for the tooltip delay.
+
+The tooltip is an overlay below the box, or on another side when it
+doesn't fit inside the root. It doesn't take the pointer, so it can't
+hide the box it belongs to. Set the Id of the box before calling Tooltip.
+
+	layout.Box().Id("save").Tooltip(func(l *gala.Layout) *gala.Box {
+		return l.Box().Text("Save the file").Padding(4)
+	})
+*/
+func (b *Box) Tooltip(build func(l *Layout) *Box) *Box {
+	l := b.layout
+	id := b.ID()
+	if l == nil || id == 0 || l.Pressed(id) || l.HoveredFor(id) < l.tooltipDelay {
+		return b
+	}
+	build(l).Overlay(b, SideBottom).PassThrough()
+	return b
+}
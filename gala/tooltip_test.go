@@ -0,0 +1,59 @@
+package gala
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTooltip(t *testing.T) {
+	l, r := newTestLayout()
+	input := &testInput{}
+	l.UseInput(input)
+	now := time.Unix(0, 0)
+	l.UseClock(func() time.Time { return now })
+	l.TooltipDelay(500 * time.Millisecond)
+
+	var tip *Box
+	frame := func(top int16) {
+		tip = nil
+		l.Box().Id("save").Position_Absolute().Left(100).Top(top).Size(50, 20).Tooltip(func(l *Layout) *Box {
+			tip = l.Box().Size(80, 30)
+			return tip
+		})
+		l.End(r)
+	}
+	r.mouseX, r.mouseY = 110, 110
+	frame(100)
+	now = now.Add(400 * time.Millisecond)
+	frame(100)
+	if tip != nil {
+		t.Fatal("the tooltip shows before the delay")
+	}
+
+	now = now.Add(200 * time.Millisecond)
+	frame(100)
+	if tip == nil {
+		t.Fatal("the tooltip doesn't show after the delay")
+	}
+	if got, want := tip.Rect(), (Rect{100, 120, 80, 30}); got != want {
+		t.Errorf("the tooltip is at %v, want below the box at %v", got, want)
+	}
+
+	// there's no room below the box at the bottom of the root
+	r.mouseY = 590
+	frame(580)
+	if tip == nil {
+		t.Fatal("the tooltip is gone after the box moved under the mouse")
+	}
+	if got, want := tip.Rect(), (Rect{100, 550, 80, 30}); got != want {
+		t.Errorf("the tooltip is at %v, want above the box at %v", got, want)
+	}
+
+	input.mouseDown, input.mousePressed = true, true
+	frame(580)
+	input.mousePressed = false
+	frame(580)
+	if tip != nil {
+		t.Error("the tooltip shows while the box is pressed")
+	}
+}
@@ -0,0 +1,91 @@
+package gala
+
+// the interaction states a style variant applies in,
+// in the order they are applied
+type interactionState int8
+
+const (
+	stateHover interactionState = iota
+	stateFocus
+	stateActive
+	stateDisabled
+)
+
+type styleVariant struct {
+	box   *Box
+	state interactionState
+	style Style
+}
+
+// HoverStyle is applied while the mouse is over the box, or one of its children.
+func (b *Box) HoverStyle(s Style) *Box {
+	return b.variant(stateHover, s)
+}
+
+// FocusStyle is applied while the box has keyboard focus.
+func (b *Box) FocusStyle(s Style) *Box {
+	return b.variant(stateFocus, s)
+}
+
+// ActiveStyle is applied while the left mouse button is held down on the box.
+func (b *Box) ActiveStyle(s Style) *Box {
+	return b.variant(stateActive, s)
+}
+
+// DisabledStyle is applied while the box is disabled, instead of the other variants.
+func (b *Box) DisabledStyle(s Style) *Box {
+	return b.variant(stateDisabled, s)
+}
+
+/*
+Disabled marks the box as disabled, it can't be focused and its
+DisabledStyle is applied. Widgets are expected to ignore the clicks
+and keys of disabled boxes.
+*/
+func (b *Box) Disabled() *Box {
+	b.disabled = true
+	return b
+}
+
+func (b *Box) variant(state interactionState, s Style) *Box {
+	if b.layout != nil {
+		b.layout.variants = append(b.layout.variants, styleVariant{b, state, s})
+	}
+	return b
+}
+
+/*
+applyVariants applies the style variants of the boxes that are in their
+state, using the pointer and focus state of the last End. It runs once
+the ids are resolved and before the boxes are sized, so variants can
+change the layout. Hover goes first, then focus, active and disabled,
+so a pressed button looks pressed while it's hovered.
+*/
+func (l *Layout) applyVariants() {
+	for state := stateHover; state <= stateDisabled; state++ {
+		for i := range l.variants {
+			v := &l.variants[i]
+			if v.state == state && l.inState(v.box, state) {
+				v.box.Apply(v.style)
+			}
+		}
+	}
+}
+
+func (l *Layout) inState(box *Box, state interactionState) bool {
+	if state == stateDisabled {
+		return box.disabled
+	}
+	if box.disabled {
+		return false
+	}
+	switch state {
+	case stateHover:
+		return l.Hovered(box.hash)
+	case stateFocus:
+		return l.Focused(box.hash)
+	case stateActive:
+		return l.Pressed(box.hash) || l.Captured(box.hash)
+	}
+	return false
+}
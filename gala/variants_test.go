@@ -0,0 +1,60 @@
+package gala
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestVariants(t *testing.T) {
+	l, r := newTestLayout()
+	input := &testInput{}
+	l.UseInput(input)
+	red := color.RGBA{255, 0, 0, 255}
+	disabled := false
+	var box, child *Box
+	frame := func() {
+		child = l.Box().Size(10, 10)
+		box = l.Box().
+			Id("button").
+			Position_Absolute().
+			Size(100, 50).
+			Padding(2).
+			HoverStyle(Style{}.Padding(10)).
+			ActiveStyle(Style{}.Padding(0).BackgroundColor(red)).
+			DisabledStyle(Style{}.Padding(4)).
+			Contains(child)
+		if disabled {
+			box.Disabled()
+		}
+		l.End(r)
+	}
+	steps := []struct {
+		name    string
+		mouse   int32
+		down    bool
+		disable bool
+		want    int16 // the x of the child, which the padding moves
+	}{
+		{"not hovered", 500, false, false, 2},
+		{"hovered", 20, false, false, 10},
+		{"pressed while hovered", 20, true, false, 0},
+		{"released", 20, false, false, 10},
+		{"disabled while hovered", 20, false, true, 4},
+	}
+	for _, step := range steps {
+		r.mouseX, r.mouseY = step.mouse, step.mouse
+		input.mousePressed = step.down && !input.mouseDown
+		input.mouseDown = step.down
+		disabled = step.disable
+		// the variants use the state of the last End
+		frame()
+		input.mousePressed = false
+		frame()
+		if got := child.Rect().X; got != step.want {
+			t.Errorf("%s: the child is at x %d, want the padding of the variant, %d", step.name, got, step.want)
+		}
+	}
+	if box.declared.padding.left != 4 {
+		t.Errorf("the declared padding is %d, want the one of the disabled variant", box.declared.padding.left)
+	}
+}
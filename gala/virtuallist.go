@@ -0,0 +1,146 @@
+package gala
+
+// rows built above and below the visible ones
+const overscan = 3
+
+type virtualListState struct {
+	scroll          int32   // offsets can be past what int16 fits
+	heights         []int16 // measured heights, 0 when unknown
+	measured, total int32
+	first           int // the index of the first row built
+	scrollTo        int
+	hasScrollTo     bool
+}
+
+/*
+VirtualList builds only the rows that are visible, so it can show any
+number of items with a fixed box pool. The builder is called with the
+index of each visible row, plus a few more above and below.
+
+When rowHeight is 0, rows are measured after they are laid out and the
+ones that weren't built yet are estimated from the measured ones.
+Otherwise rows without a height get rowHeight.
+The scroll is kept per id and changes with the mouse wheel.
+It's 300 by 200 pixels by default.
+
+	layout.VirtualList("log", len(lines), 20, func(i int) *gala.Box {
+		return layout.Box().Text(lines[i])
+	}).Flex(1)
+*/
+func (l *Layout) VirtualList(id string, count int, rowHeight int16, build func(i int) *Box) *Box {
+	box := l.Box().
+		Id(id).
+		Overflow_Hidden().
+		FlexDirection_Column().
+		Size(300, 200).
+		Scrollable()
+	s := State[virtualListState](l, box.ID())
+	// rows added or removed at the end keep the heights of the others
+	if count > len(s.heights) {
+		s.heights = append(s.heights, make([]int16, count-len(s.heights))...)
+	}
+	for _, height := range s.heights[count:] {
+		if height > 0 {
+			s.measured--
+			s.total -= int32(height)
+		}
+	}
+	s.heights = s.heights[:count]
+
+	viewport := int32(l.rootBox.height)
+	if r, ok := l.Rect(box.ID()); ok {
+		viewport = int32(r.Height)
+	}
+	if s.hasScrollTo && s.scrollTo >= 0 && s.scrollTo < count {
+		top := s.offset(s.scrollTo, rowHeight)
+		bottom := top + int32(s.height(s.scrollTo, rowHeight))
+		s.scroll = min(s.scroll, top)
+		s.scroll = max(s.scroll, bottom-viewport)
+	}
+	s.hasScrollTo = false
+	s.scroll = max(0, min(s.scroll, s.offset(count, rowHeight)-viewport))
+
+	// find the visible rows, then add the overscan
+	start, y := 0, int32(0)
+	for start < count && y+int32(s.height(start, rowHeight)) <= s.scroll {
+		y += int32(s.height(start, rowHeight))
+		start++
+	}
+	end := start
+	for end < count && y < s.scroll+viewport {
+		y += int32(s.height(end, rowHeight))
+		end++
+	}
+	start, end = max(0, start-overscan), min(count, end+overscan)
+	s.first = start
+
+	content := l.Box().
+		FlexDirection_Column().
+		Width(Percent(100)).
+		MarginTop(int16(s.offset(start, rowHeight) - s.scroll))
+	for i := start; i < end; i++ {
+		row := build(i)
+		if rowHeight > 0 && row.height == 0 {
+			row.Height(float32(rowHeight))
+		}
+		content.Contains(row)
+	}
+	box.update = func(box *Box) {
+		l.scrollVirtualList(box, content, rowHeight)
+	}
+	return box.Contains(content)
+}
+
+// ScrollListTo scrolls the VirtualList with the id just enough
+// for the row at index to be visible, when it's built next.
+func (l *Layout) ScrollListTo(id ID, index int) {
+	s := State[virtualListState](l, id)
+	s.scrollTo, s.hasScrollTo = index, true
+}
+
+// measures the built rows and scrolls with the mouse wheel, at End
+func (l *Layout) scrollVirtualList(box, content *Box, rowHeight int16) {
+	s := State[virtualListState](l, box.hash)
+	if rowHeight == 0 {
+		for i, p := range content.children {
+			index := s.first + i
+			if index >= len(s.heights) {
+				break
+			}
+			height := int16(p.height) + p.margin.top + p.margin.bottom
+			if s.heights[index] == 0 {
+				s.measured++
+			} else {
+				s.total -= int32(s.heights[index])
+			}
+			s.heights[index] = max(1, height)
+			s.total += int32(s.heights[index])
+		}
+	}
+	s.scroll -= int32(l.ConsumeWheel(box.hash) * 3 * float32(s.height(s.first, rowHeight)))
+}
+
+// the height of the row, estimated when it wasn't measured yet
+func (s *virtualListState) height(i int, rowHeight int16) int16 {
+	switch {
+	case rowHeight > 0:
+		return rowHeight
+	case i < len(s.heights) && s.heights[i] > 0:
+		return s.heights[i]
+	case s.measured > 0:
+		return int16(s.total / s.measured)
+	}
+	return lineHeight(defaultFontSize)
+}
+
+// the offset of the top of the row from the top of the first one
+func (s *virtualListState) offset(i int, rowHeight int16) int32 {
+	if rowHeight > 0 {
+		return int32(i) * int32(rowHeight)
+	}
+	var y int32
+	for j := range i {
+		y += int32(s.height(j, rowHeight))
+	}
+	return y
+}
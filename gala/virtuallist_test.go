@@ -0,0 +1,54 @@
+package gala
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestVirtualListKeepsHeights(t *testing.T) {
+	l, r := newTestLayout()
+	count := 20
+	var list *Box
+	frame := func() {
+		list = l.VirtualList("list", count, 0, func(i int) *Box {
+			return l.Box().Height(float32(10 + i%3*10))
+		})
+		l.End(r)
+	}
+	// the first frame doesn't know the height of the list yet
+	for range 3 {
+		frame()
+	}
+	s := State[virtualListState](l, list.ID())
+	measured := slices.Clone(s.heights)
+	total := func(heights []int16) (sum, n int32) {
+		for _, height := range heights {
+			if height > 0 {
+				sum += int32(height)
+				n++
+			}
+		}
+		return
+	}
+	if sum, n := total(measured); n == 0 || s.total != sum || s.measured != n {
+		t.Fatalf("total %d of %d rows, want %d of %d", s.total, s.measured, sum, n)
+	}
+
+	count = 25
+	frame()
+	if !slices.Equal(s.heights[:20], measured) {
+		t.Errorf("heights %v after adding rows, want %v kept", s.heights[:20], measured)
+	}
+	if sum, n := total(measured); s.total != sum || s.measured != n {
+		t.Errorf("total %d of %d rows after adding rows, want %d of %d", s.total, s.measured, sum, n)
+	}
+
+	count = 5
+	frame()
+	if !slices.Equal(s.heights, measured[:5]) {
+		t.Errorf("heights %v after removing rows, want %v", s.heights, measured[:5])
+	}
+	if sum, n := total(measured[:5]); s.total != sum || s.measured != n {
+		t.Errorf("total %d of %d rows after removing rows, want %d of %d", s.total, s.measured, sum, n)
+	}
+}
@@ -0,0 +1,139 @@
+package gala
+
+import (
+	"errors"
+	"image/color"
+	"os"
+	"time"
+)
+
+// how often the layout checks watched files for changes
+const watchInterval = 250 * time.Millisecond
+
+/*
+Watcher reloads a file when it changes on disk, it's found out by polling
+its modification time and size, so it works the same everywhere.
+
+When the file can't be read or parsed the last good value is kept and
+the error is reported by Err until a later version loads.
+*/
+type Watcher[T any] struct {
+	path     string
+	parse    func(src string) (T, error)
+	onChange func(value T)
+	modTime  time.Time
+	size     int64
+	value    T
+	err      error
+}
+
+// Watch creates a watcher of the file, nothing is read until the first Poll.
+func Watch[T any](path string, parse func(src string) (T, error)) *Watcher[T] {
+	return &Watcher[T]{path: path, parse: parse}
+}
+
+// OnChange is called with the new value every time the file is reloaded.
+func (w *Watcher[T]) OnChange(onChange func(value T)) *Watcher[T] {
+	w.onChange = onChange
+	return w
+}
+
+// Poll reloads the file when it changed since the last Poll,
+// it reports whether a new value was loaded.
+func (w *Watcher[T]) Poll() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		w.fail(err)
+		return false
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	src, err := os.ReadFile(w.path)
+	if err != nil {
+		w.fail(err)
+		return false
+	}
+	value, err := w.parse(string(src))
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) && parseErr.File == "" {
+			parseErr.File = w.path
+		}
+		w.err = err
+		return false
+	}
+	w.value, w.err = value, nil
+	if w.onChange != nil {
+		w.onChange(value)
+	}
+	return true
+}
+
+// fail keeps the error of a file that can't be read until it can be again.
+// The file may come back with the same time and size, like when an editor
+// saves it by renaming another file over it, so it's read again then.
+func (w *Watcher[T]) fail(err error) {
+	w.modTime, w.size, w.err = time.Time{}, 0, err
+}
+
+// Value returns the last version of the file that loaded.
+func (w *Watcher[T]) Value() T {
+	return w.value
+}
+
+// Err returns why the file didn't load the last time it changed, nil when it did.
+func (w *Watcher[T]) Err() error {
+	return w.err
+}
+
+// Polled is implemented by watchers, whatever they load.
+type Polled interface {
+	Poll() bool
+	Err() error
+}
+
+// AddWatcher makes the layout poll the watcher between frames,
+// and show its error over everything else while it has one.
+func (l *Layout) AddWatcher(w Polled) {
+	l.watchers = append(l.watchers, w)
+}
+
+// WatchStylesheet uses the stylesheet in the file,
+// reloading it whenever it's changed.
+func (l *Layout) WatchStylesheet(path string) *Watcher[*Stylesheet] {
+	w := Watch(path, ParseStylesheet).OnChange(l.UseStylesheet)
+	w.Poll()
+	l.AddWatcher(w)
+	return w
+}
+
+// called in beginFrame, so files are swapped before any box is declared
+func (l *Layout) pollWatchers() {
+	if len(l.watchers) == 0 || l.now.Sub(l.lastWatch) < watchInterval {
+		return
+	}
+	l.lastWatch = l.now
+	for _, w := range l.watchers {
+		w.Poll()
+	}
+}
+
+var watchErrorColor = color.RGBA{160, 30, 30, 230}
+
+// drawWatchErrors draws the errors of the watchers at the bottom of the screen
+func (l *Layout) drawWatchErrors(renderer Renderer) {
+	const fontSize, padding = 16, 6
+	y := int32(l.rootBox.height)
+	width := int32(l.rootBox.width)
+	for i := len(l.watchers) - 1; i >= 0; i-- {
+		err := l.watchers[i].Err()
+		if err == nil {
+			continue
+		}
+		y -= fontSize + padding*2
+		renderer.DrawRect(0, y, width, fontSize+padding*2, watchErrorColor)
+		renderer.DrawText(err.Error(), padding, y+padding, fontSize, color.RGBA{255, 255, 255, 255})
+	}
+}
@@ -0,0 +1,86 @@
+package gala
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// parseNumber parses a file holding a number
+func parseNumber(src string) (int, error) {
+	i, err := strconv.Atoi(src)
+	if err != nil {
+		return 0, &ParseError{Line: 1, Column: 1, Message: "not a number"}
+	}
+	return i, nil
+}
+
+func TestWatcherPoll(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "number")
+	moved := filepath.Join(dir, "moved")
+	stamp := time.Now().Add(-time.Hour)
+	// writes the file with a modification time that only changes when asked,
+	// so the test doesn't depend on the resolution of the file system
+	write := func(src string, touch bool) {
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if touch {
+			stamp = stamp.Add(time.Second)
+		}
+		if err := os.Chtimes(path, stamp, stamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rename := func(from, to string) {
+		if err := os.Rename(from, to); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var changes []int
+	w := Watch(path, parseNumber).OnChange(func(i int) { changes = append(changes, i) })
+	steps := []struct {
+		name   string
+		change func()
+		loaded bool
+		value  int
+		err    string
+	}{
+		{"missing", func() {}, false, 0, "not exist"},
+		{"created", func() { write("1", true) }, true, 1, ""},
+		{"unchanged", func() {}, false, 1, ""},
+		{"longer", func() { write("22", false) }, true, 22, ""},
+		{"same size, touched", func() { write("33", true) }, true, 33, ""},
+		{"same size and time", func() { write("44", false) }, false, 33, ""},
+		{"broken", func() { write("x", true) }, false, 33, path + ":1:1: not a number"},
+		{"fixed", func() { write("5", true) }, true, 5, ""},
+		{"renamed away", func() { rename(path, moved) }, false, 5, "not exist"},
+		{"renamed back", func() { rename(moved, path) }, true, 5, ""},
+	}
+	for _, step := range steps {
+		step.change()
+		if loaded := w.Poll(); loaded != step.loaded {
+			t.Errorf("%s: Poll = %v, want %v", step.name, loaded, step.loaded)
+		}
+		if w.Value() != step.value {
+			t.Errorf("%s: value %d, want %d", step.name, w.Value(), step.value)
+		}
+		switch err := w.Err(); {
+		case step.err == "" && err != nil:
+			t.Errorf("%s: error %v, want none", step.name, err)
+		case step.err == "not exist" && !errors.Is(err, os.ErrNotExist):
+			t.Errorf("%s: error %v, want a missing file", step.name, err)
+		case step.err != "" && step.err != "not exist" && (err == nil || err.Error() != step.err):
+			t.Errorf("%s: error %v, want %s", step.name, err, step.err)
+		}
+	}
+	if want := []int{1, 22, 33, 5, 5}; !slices.Equal(changes, want) {
+		t.Errorf("OnChange was called with %v, want %v", changes, want)
+	}
+}
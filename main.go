@@ -1,11 +1,20 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"gala/gala"
+	"gala/markup"
 	"gala/renderers"
+	"gala/widgets"
 	"image/color"
+	"log"
 	"math/rand"
 	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	// _ "net/http/pprof" // Import the pprof package to register pprof handlers
 
@@ -20,35 +29,183 @@ func main() {
 		http.ListenAndServe(":8080", nil) // Start the pprof server on port 6060
 	}()
 
-	var layout = gala.NewLayout(1280, 720, 199)
+	var layout = gala.NewLayout(1280, 720, 499)
+	layout.DebugMode(true)
+	layout.UseInput(renderer)
+	// edit style.css while the demo runs, it's reloaded
+	layout.WatchStylesheet("style.css")
+	inspector := markup.Watch(&layout, "inspector.xml")
+	layout.UseClipboard(renderer)
+	var name, notes string
+	var subscribed bool
+	var volume float32 = 50
+	var quality int
+	var confirming bool
+	type file struct {
+		name string
+		size int
+	}
+	files := make([]file, 1000)
+	for i := range files {
+		files[i] = file{fmt.Sprintf("file%03d.txt", i), rand.Intn(100000)}
+	}
+	selectedFile := -1
+	var folder func(depth int) []widgets.TreeNode
+	folder = func(depth int) []widgets.TreeNode {
+		nodes := make([]widgets.TreeNode, 3)
+		for i := range nodes {
+			nodes[i] = widgets.TreeNode{Id: strconv.Itoa(i), Text: fmt.Sprintf("Folder %d", i)}
+			if depth < 3 {
+				nodes[i].Children = func() []widgets.TreeNode { return folder(depth + 1) }
+			}
+		}
+		return nodes
+	}
+	var selectedFolder string
+	tabs := []widgets.Tab{
+		{Id: "files", Title: "Files"},
+		{Id: "folders", Title: "Folders"},
+	}
+	for i := range 5 {
+		tabs = append(tabs, widgets.Tab{Id: fmt.Sprint("scratch", i), Title: fmt.Sprint("Scratch ", i), Closable: true})
+	}
+	sortFiles := func(column int, order widgets.SortOrder) {
+		slices.SortFunc(files, func(a, b file) int {
+			c := strings.Compare(a.name, b.name)
+			if column == 1 {
+				c = a.size - b.size
+			}
+			if order == widgets.Descending {
+				return -c
+			}
+			return c
+		})
+	}
+	// the dock layout is kept between runs
+	dock := widgets.DockNode{Children: []*widgets.DockNode{
+		{Panels: []string{"Scene", "Game"}},
+		{Panels: []string{"Inspector"}},
+	}}
+	if data, err := os.ReadFile("dock.json"); err == nil {
+		// a broken file would leave the tree half filled, keep the default one
+		var saved widgets.DockNode
+		if err := json.Unmarshal(data, &saved); err != nil {
+			log.Printf("dock.json: %v, using the default layout", err)
+		} else {
+			dock = saved
+		}
+	}
+	defer func() {
+		if data, err := json.Marshal(&dock); err == nil {
+			os.WriteFile("dock.json", data, 0o644)
+		}
+	}()
+	ui := widgets.New(&layout, widgets.DefaultTheme)
 	rl.InitWindow(1280, 720, "yo")
 	rl.SetTargetFPS(60)
+	panelStyle := gala.Style{}.
+		FlexDirection_Column().
+		Padding(10).
+		BackgroundColor(color.RGBA{40, 40, 46, 255}).
+		Border(1, color.RGBA{88, 101, 242, 255})
 	for !rl.WindowShouldClose() {
 		rl.BeginDrawing()
 		rl.ClearBackground(rl.White)
-		layout.Box().
+		clearButton, cleared := ui.Button("clear", "Clear")
+		if cleared {
+			confirming = true
+		}
+		ui.Tooltip(clearButton, "Clears the name and the notes")
+		if confirming {
+			yes, clearNow := ui.Button("yes", "Clear")
+			no, cancel := ui.Button("no", "Cancel")
+			if clearNow {
+				name, notes = "", ""
+			}
+			confirming = !clearNow && !cancel
+			layout.Modal("confirm", func() { confirming = false }).Contains(
+				layout.Box().
+					Apply(panelStyle).
+					Contains(
+						layout.Box().Text("Clear the name and the notes?").TextColor(rl.RayWhite),
+						layout.Box().FlexDirection_Row().Contains(yes, no),
+					),
+			)
+		}
+		subscribe, _ := ui.Checkbox("subscribe", "Subscribe", &subscribed)
+		volumeSlider, _ := ui.Slider("volume", &volume, 0, 100, 5)
+		views, _ := ui.Tabs("views", tabs, func(tab widgets.Tab) *gala.Box {
+			switch tab.Id {
+			case "files":
+				fileTable, _ := ui.Table("files", []widgets.Column{
+					{Title: "Name", Flex: 2, Sortable: true},
+					{Title: "Size", Width: 100, Sortable: true},
+				}, len(files), func(row, column int) string {
+					if column == 1 {
+						return strconv.Itoa(files[row].size)
+					}
+					return files[row].name
+				}, &selectedFile, sortFiles)
+				return fileTable
+			case "folders":
+				folders, _ := ui.TreeView("folders", folder(0), &selectedFolder)
+				return folders
+			}
+			return layout.Box().Text(tab.Title).TextColorToken("text")
+		}, func(id string) {
+			tabs = slices.DeleteFunc(tabs, func(tab widgets.Tab) bool { return tab.Id == id })
+		})
+		qualitySelect, _ := ui.Select("quality", []string{"Low", "Medium", "High"}, &quality)
+		menuBar := ui.MenuBar("menu", []widgets.Menu{
+			{Text: "File", Items: []widgets.MenuItem{
+				{Text: "New", Shortcut: widgets.Shortcut{Key: gala.KeyN, Control: true}, Action: func() { name, notes = "", "" }},
+				{Text: "Open", Shortcut: widgets.Shortcut{Key: gala.KeyO, Control: true}, Disabled: true},
+				{},
+				{Text: "Quality", Items: []widgets.MenuItem{
+					{Text: "Low", Action: func() { quality = 0 }},
+					{Text: "Medium", Action: func() { quality = 1 }},
+					{Text: "High", Action: func() { quality = 2 }},
+				}},
+			}},
+			{Text: "View", Items: []widgets.MenuItem{
+				{Text: "Subscribe", Shortcut: widgets.Shortcut{Key: gala.KeyS, Control: true, Shift: true}, Action: func() { subscribed = !subscribed }},
+				{Text: "Dark theme", Disabled: layout.Theme().Name == "dark", Action: func() { layout.UseTheme(gala.DarkTheme()) }},
+				{Text: "Light theme", Disabled: layout.Theme().Name == "light", Action: func() { layout.UseTheme(gala.LightTheme()) }},
+			}},
+		})
+		card := layout.Box().
 			Id("Card").
-			Size(gala.Percent(100), gala.Percent(100)).
+			Width(gala.Percent(100)).
+			Flex(1).
 			Display_Flex().
 			AlignItems_Center().
-			BackgroundColor(color.RGBA{26, 26, 29, 255}).
+			BackgroundToken("background").
 			FlexDirection_Row().
 			Padding(10).
 			Contains(
-				layout.Box().
-					Size(100, 100).
-					Id("sigma").
-					BackgroundColor(rl.Blue).
-					Flex(1).
-					Display_Flex(),
-
-				layout.Box().
-					Size(100, 100).
-					Id("sigma").
-					BackgroundColor(rl.Beige).
-					Flex(1).
-					Display_Flex(),
-				layout.Box().
+				ui.SplitPane("split",
+					widgets.Pane{
+						Box: ui.Dock("dock", &dock, func(panel string) *gala.Box {
+							if panel == "Inspector" {
+								// errors are shown over the layout until they're fixed
+								box, _ := inspector.Build(&layout, map[string]any{
+									"Name":       name,
+									"Subscribed": subscribed,
+									"Volume":     volume,
+								}, markup.Handlers{
+									"subscribe": func(*gala.Box) { subscribed = !subscribed },
+								})
+								if box != nil {
+									return box
+								}
+							}
+							return layout.Box().Text(panel).TextColorToken("text")
+						}),
+						Min: 50,
+					},
+					widgets.Pane{Box: views, Min: 200},
+				).Flex(2),
+				ui.ContextMenu("pinkMenu", layout.Box().
 					Height(200).
 					Left(20).
 					Right(10).
@@ -56,10 +213,34 @@ func main() {
 					// Position_Relative().
 					Flex(1).
 					BackgroundColor(rl.Pink).
-					Hovered(func(box *gala.Box) {
-						box.BackgroundColor(color.RGBA{0, uint8(rand.Intn(255)), uint8(rand.Intn(255)), 255})
-					}),
+					HoverStyle(gala.Style{}.BackgroundColor(color.RGBA{0, 150, 136, 255})).
+					ActiveStyle(gala.Style{}.Margin(4)), []widgets.MenuItem{
+					{Text: "Clear the log", Disabled: true},
+					{Text: "Subscribe", Action: func() { subscribed = !subscribed }},
+				}),
+				layout.Box().
+					FlexDirection_Column().
+					Contains(
+						layout.TextInput("name", &name, "Your name").
+							BackgroundColor(rl.RayWhite),
+						layout.TextArea("notes", &notes).
+							BackgroundColor(rl.RayWhite),
+						subscribe,
+						volumeSlider,
+						qualitySelect,
+						clearButton,
+						layout.VirtualList("log", 10000, 20, func(i int) *gala.Box {
+							return layout.Box().
+								Text(fmt.Sprintf("Line %d", i)).
+								Class("line")
+						}),
+					),
 			)
+		layout.Box().
+			Id("App").
+			Size(gala.Percent(100), gala.Percent(100)).
+			FlexDirection_Column().
+			Contains(menuBar, card)
 		layout.End(renderer)
 
 		rl.EndDrawing()
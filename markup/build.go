@@ -0,0 +1,174 @@
+package markup
+
+import (
+	"fmt"
+	"gala/gala"
+	"reflect"
+	"strings"
+)
+
+/*
+Build declares the boxes of the document on the layout, with the data
+its templates and paths use and the handlers its events name. It's
+called every frame, like building boxes in Go.
+
+Errors don't stop the build, the boxes that failed are built without
+the property that failed and the first error is returned, it's a
+*gala.ParseError at the element that failed.
+*/
+func (d *Document) Build(l *gala.Layout, data any, handlers Handlers) (*gala.Box, error) {
+	b := builder{l: l, handlers: handlers}
+	boxes := b.build(d.root, data, nil)
+	return boxes[0], b.err
+}
+
+type builder struct {
+	l        *gala.Layout
+	handlers Handlers
+	err      error // the first one
+}
+
+func (b *builder) fail(n *node, format string, args ...any) {
+	if b.err == nil {
+		b.err = errorAt(n.line, n.col, format, args...)
+	}
+}
+
+// build appends the boxes of the node to boxes, nodes other
+// than boxes build the boxes of their children
+func (b *builder) build(n *node, data any, boxes []*gala.Box) []*gala.Box {
+	switch n.kind {
+	case kindEach:
+		items, err := lookup(data, n.path)
+		if err != nil {
+			b.fail(n, "%v", err)
+			return boxes
+		}
+		if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+			b.fail(n, "%s is a %s, not a slice", n.path, items.Kind())
+			return boxes
+		}
+		for i := range items.Len() {
+			for _, child := range n.children {
+				boxes = b.build(child, items.Index(i).Interface(), boxes)
+			}
+		}
+		return boxes
+	case kindIf, kindUnless:
+		v, err := lookup(data, n.path)
+		if err != nil {
+			b.fail(n, "%v", err)
+			return boxes
+		}
+		if (v.IsValid() && !v.IsZero()) == (n.kind == kindIf) {
+			for _, child := range n.children {
+				boxes = b.build(child, data, boxes)
+			}
+		}
+		return boxes
+	}
+
+	box := b.l.Box().Apply(n.style)
+	eval := func(v value) string {
+		text, err := v.eval(data)
+		if err != nil {
+			b.fail(n, "%v", err)
+		}
+		return text
+	}
+	if id := eval(n.id); id != "" {
+		box.Id(id)
+	}
+	if class := eval(n.class); class != "" {
+		box.Class(class)
+	}
+	if text := eval(n.text); text != "" {
+		box.Text(text)
+	}
+	for _, dynamic := range n.dynamic {
+		style, err := gala.ParseStyle(dynamic.property + eval(dynamic.value))
+		if err != nil {
+			b.fail(n, "%v", styleError(err, ""))
+			continue
+		}
+		box.Apply(style)
+	}
+	if n.focusable {
+		box.Focusable()
+	}
+	if n.disabled {
+		box.Disabled()
+	}
+	b.events(n, box)
+
+	var children []*gala.Box
+	for _, child := range n.children {
+		children = b.build(child, data, children)
+	}
+	return append(boxes, box.Contains(children...))
+}
+
+// events calls or sets the handlers of the events of the box
+func (b *builder) events(n *node, box *gala.Box) {
+	for event, name := range n.events {
+		handler, ok := b.handlers[name]
+		if !ok {
+			b.fail(n, "%s: unknown handler %q", event, name)
+			continue
+		}
+		switch event {
+		case "onclick":
+			if b.l.Clicked(box.ID()) {
+				handler(box)
+			}
+		case "onhover":
+			box.Hovered(handler)
+		case "onfocus":
+			box.OnFocus(handler)
+		case "onblur":
+			box.OnBlur(handler)
+		}
+	}
+}
+
+// lookup finds the value at the path, like .User.Name, in the data,
+// it's invalid when a map doesn't have a key or a pointer is nil
+func lookup(data any, path string) (reflect.Value, error) {
+	v := reflect.ValueOf(data)
+	for _, name := range strings.Split(strings.TrimPrefix(path, "."), ".") {
+		if name == "" {
+			continue
+		}
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() || v.MethodByName(name).IsValid() {
+				break
+			}
+			v = v.Elem()
+		}
+		// missing keys and nil pointers are zero values, like in templates
+		if !v.IsValid() || (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+			return reflect.Value{}, nil
+		}
+		if method := v.MethodByName(name); method.IsValid() && method.Type().NumIn() == 0 &&
+			method.Type().NumOut() == 1 {
+			v = method.Call(nil)[0]
+			continue
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			field := v.FieldByName(name)
+			if !field.IsValid() || !field.CanInterface() {
+				return v, fmt.Errorf("%s has no field %s", path, name)
+			}
+			v = field
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return v, fmt.Errorf("%s: the keys of the map aren't strings", path)
+			}
+			v = v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		default:
+			return v, fmt.Errorf("%s: can't find %s in a %s", path, name, v.Kind())
+		}
+	}
+	return v, nil
+}
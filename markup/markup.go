@@ -0,0 +1,350 @@
+/*
+Package markup builds trees of boxes out of documents written in an
+XML like format, so screens can be written without Go:
+
+	<box id="Card" direction="row" padding="10" bg="#1a1a1d">
+		<each items=".Files">
+			<box class="file" onclick="open" text="{{.Name}}"/>
+		</each>
+		<if test=".Subscribed">
+			<box color="white">Thanks for subscribing, {{.Name}}</box>
+		</if>
+	</box>
+
+A document is parsed once and built every frame with Build, which
+declares its boxes on a layout.
+
+# Boxes
+
+Attributes of a box are the properties of stylesheets, like
+flex-direction or font-size, with a few shorter names: direction for
+flex-direction, justify for justify-content, align for align-items,
+bg for background-color and radius for border-radius. A style attribute
+takes declarations, like style="padding: 4px; color: white".
+
+id and class set the Id and Class of the box, text or the text inside
+the element its text. A box has either text inside or other elements,
+not both. focusable="true" and disabled="true" make it focusable or
+disabled.
+
+# Data
+
+Attributes and text can use text/template actions on the data passed to
+Build, like text="{{.Name}}". They're evaluated every frame, attributes
+without actions are parsed only once.
+
+<each items=".Path"> builds its children for every element of the slice
+or array at the path, with the element as the data. <if test=".Path">
+builds its children when the value at the path isn't the zero value,
+<unless test=".Path"> when it is. Paths are made of field names, map
+keys and methods without arguments, "." is the data itself.
+
+# Events
+
+onclick, onhover, onfocus and onblur name the handler called when the
+box is clicked, hovered, focused or blurred. Handlers are looked up by
+name in the Handlers passed to Build.
+*/
+package markup
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"gala/gala"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Handlers are the event handlers documents can name, by name.
+type Handlers map[string]func(box *gala.Box)
+
+// Document is a parsed document, it's built with Build.
+type Document struct {
+	root *node
+}
+
+type nodeKind int8
+
+const (
+	kindBox nodeKind = iota
+	kindEach
+	kindIf
+	kindUnless
+)
+
+var kinds = map[string]nodeKind{
+	"box":    kindBox,
+	"each":   kindEach,
+	"if":     kindIf,
+	"unless": kindUnless,
+}
+
+type node struct {
+	kind      nodeKind
+	line, col int
+
+	// boxes
+	style     gala.Style     // of the attributes without actions
+	dynamic   []dynamicStyle // attributes with actions, parsed every frame
+	id, class value
+	text      value
+	inner     bool // the text is the one inside the element
+	focusable bool
+	disabled  bool
+	events    map[string]string // handler names by event
+	children  []*node
+
+	// each, if and unless
+	path string
+}
+
+type dynamicStyle struct {
+	property string // like "padding: ", empty for the style attribute
+	value    value
+}
+
+// value is the text of an attribute, with or without template actions
+type value struct {
+	text     string
+	template *template.Template
+}
+
+func (v value) eval(data any) (string, error) {
+	if v.template == nil {
+		return v.text, nil
+	}
+	var b strings.Builder
+	err := v.template.Execute(&b, data)
+	return b.String(), err
+}
+
+// the shorter names of properties
+var aliases = map[string]string{
+	"direction": "flex-direction",
+	"justify":   "justify-content",
+	"align":     "align-items",
+	"bg":        "background-color",
+	"radius":    "border-radius",
+}
+
+var events = map[string]bool{"onclick": true, "onhover": true, "onfocus": true, "onblur": true}
+
+// Parse parses the document, the error is a *gala.ParseError.
+func Parse(src string) (*Document, error) {
+	d := xml.NewDecoder(strings.NewReader(src))
+	var stack []*node
+	var doc Document
+	for {
+		// the position of the token that's about to be read
+		line, col := d.InputPos()
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, syntaxError(d, err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			n, err := newNode(t, line, col)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case len(stack) > 0 && stack[len(stack)-1].inner:
+				return nil, errorAt(line, col, "text can't be mixed with elements, put it in a box")
+			case len(stack) > 0:
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			case doc.root != nil:
+				return nil, errorAt(line, col, "a document has a single root element")
+			case n.kind != kindBox:
+				return nil, errorAt(line, col, "the root element has to be a box")
+			default:
+				doc.root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" || len(stack) == 0 {
+				continue
+			}
+			parent := stack[len(stack)-1]
+			if parent.kind != kindBox {
+				return nil, errorAt(line, col, "text has to be inside a box")
+			}
+			if len(parent.children) > 0 {
+				return nil, errorAt(line, col, "text can't be mixed with elements, put it in a box")
+			}
+			if parent.inner {
+				// split by a comment
+				text = parent.text.text + " " + text
+			}
+			parent.inner = true
+			if parent.text, err = compile(text); err != nil {
+				return nil, errorAt(line, col, "%v", err)
+			}
+		}
+	}
+	if doc.root == nil {
+		return nil, errorAt(1, 1, "the document has no elements")
+	}
+	return &doc, nil
+}
+
+// newNode builds the node of the element and parses its attributes
+func newNode(element xml.StartElement, line, col int) (*node, error) {
+	kind, ok := kinds[element.Name.Local]
+	if !ok {
+		return nil, errorAt(line, col, "unknown element <%s>", element.Name.Local)
+	}
+	n := &node{kind: kind, line: line, col: col}
+	for _, attr := range element.Attr {
+		if err := n.attribute(attr.Name.Local, attr.Value); err != nil {
+			return nil, errorAt(line, col, "%s: %v", attr.Name.Local, err)
+		}
+	}
+	if kind != kindBox && n.path == "" {
+		attr := "test"
+		if kind == kindEach {
+			attr = "items"
+		}
+		return nil, errorAt(line, col, "<%s> needs the %s attribute", element.Name.Local, attr)
+	}
+	return n, nil
+}
+
+func (n *node) attribute(name, text string) error {
+	if n.kind != kindBox {
+		switch {
+		case n.kind == kindEach && name == "items", n.kind != kindEach && name == "test":
+			if !strings.HasPrefix(text, ".") {
+				return fmt.Errorf("expected a path like .Name, found %q", text)
+			}
+			n.path = text
+			return nil
+		}
+		return errors.New("unknown attribute")
+	}
+	v, err := compile(text)
+	if err != nil {
+		return err
+	}
+	switch {
+	case name == "id":
+		n.id = v
+	case name == "class":
+		n.class = v
+	case name == "text":
+		n.text = v
+	case name == "focusable":
+		n.focusable = text == "true"
+	case name == "disabled":
+		n.disabled = text == "true"
+	case events[name]:
+		if n.events == nil {
+			n.events = make(map[string]string)
+		}
+		n.events[name] = text
+	default:
+		property := name + ": "
+		if alias, ok := aliases[name]; ok {
+			property = alias + ": "
+		}
+		if name == "style" {
+			property = ""
+		}
+		if v.template != nil {
+			n.dynamic = append(n.dynamic, dynamicStyle{property, v})
+			return nil
+		}
+		style, err := gala.ParseStyle(property + text)
+		if err != nil {
+			return styleError(err, property)
+		}
+		n.style = n.style.Merge(style)
+	}
+	return nil
+}
+
+// compile parses the template actions of the text, if it has any
+func compile(text string) (value, error) {
+	if !strings.Contains(text, "{{") {
+		return value{text: text}, nil
+	}
+	t, err := template.New("").Parse(text)
+	if err != nil {
+		return value{}, err
+	}
+	return value{text: text, template: t}, nil
+}
+
+// the message of a style error, its position is the one of the element.
+// The property is left out, errors of attributes are prefixed with their name.
+func styleError(err error, property string) error {
+	var parseErr *gala.ParseError
+	if errors.As(err, &parseErr) {
+		return errors.New(strings.TrimPrefix(parseErr.Message, property))
+	}
+	return err
+}
+
+func errorAt(line, col int, format string, args ...any) error {
+	return &gala.ParseError{Line: line, Column: col, Message: fmt.Sprintf(format, args...)}
+}
+
+// syntaxError turns an error of the decoder into a ParseError at its position
+func syntaxError(d *xml.Decoder, err error) error {
+	line, col := d.InputPos()
+	message := err.Error()
+	var syntax *xml.SyntaxError
+	if errors.As(err, &syntax) {
+		line, message = syntax.Line, syntax.Msg
+	}
+	return errorAt(line, col, "%s", message)
+}
+
+// Watched is a document in a file, reloaded whenever the file changes.
+type Watched struct {
+	*gala.Watcher[*Document]
+	path     string
+	buildErr error // of the last Build
+}
+
+// Watch loads the document in the file and reloads it whenever it's changed,
+// its errors are shown over the layout like the ones of stylesheets.
+func Watch(l *gala.Layout, path string) *Watched {
+	w := &Watched{Watcher: gala.Watch(path, Parse), path: path}
+	w.Poll()
+	l.AddWatcher(w)
+	return w
+}
+
+// Build builds the last version of the document that loaded, like
+// Document.Build, the box is nil while none has. Its error is shown over
+// the layout too, until a later Build succeeds.
+func (w *Watched) Build(l *gala.Layout, data any, handlers Handlers) (*gala.Box, error) {
+	doc := w.Value()
+	if doc == nil {
+		return nil, nil
+	}
+	box, err := doc.Build(l, data, handlers)
+	var parseErr *gala.ParseError
+	if errors.As(err, &parseErr) {
+		parseErr.File = w.path
+	}
+	w.buildErr = err
+	return box, err
+}
+
+// Err returns why the file didn't load, or else why the last Build failed.
+func (w *Watched) Err() error {
+	if err := w.Watcher.Err(); err != nil {
+		return err
+	}
+	return w.buildErr
+}
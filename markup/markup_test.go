@@ -0,0 +1,165 @@
+package markup
+
+import (
+	"errors"
+	"gala/gala"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testRenderer draws nothing, every character is half the font size wide
+type testRenderer struct{}
+
+func (testRenderer) DrawRect(posX, posY, width, height int32, color color.RGBA)     {}
+func (testRenderer) DrawBorder(posX, posY, width, height int32, border gala.Border) {}
+func (testRenderer) DrawText(text string, posX, posY, fontSize int32, c color.RGBA) {}
+func (testRenderer) BeginScissor(posX, posY, width, height int32)                   {}
+func (testRenderer) EndScissor()                                                    {}
+func (testRenderer) MousePos() (x int32, y int32)                                   { return -1, -1 }
+
+func (testRenderer) MeasureText(text string, fontSize int32) int32 {
+	return int32(len(text)) * fontSize / 2
+}
+
+// outline writes the boxes of the snapshot as "id(text)[children]"
+func outline(s gala.Snapshot) string {
+	var b strings.Builder
+	b.WriteString(s.Id)
+	if s.Text != "" {
+		b.WriteString("(" + s.Text + ")")
+	}
+	if len(s.Children) > 0 {
+		children := make([]string, len(s.Children))
+		for i, child := range s.Children {
+			children[i] = outline(child)
+		}
+		b.WriteString("[" + strings.Join(children, " ") + "]")
+	}
+	return b.String()
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"", "1:1: the document has no elements"},
+		{"<box/><box/>", "1:7: a document has a single root element"},
+		{"<if test=\".A\"/>", "1:1: the root element has to be a box"},
+		{"<box>\n  <span/>\n</box>", "2:3: unknown element <span>"},
+		{"<box>\n  <each/>\n</box>", "2:3: <each> needs the items attribute"},
+		{"<box><each items=\"Files\"/></box>", `1:6: items: expected a path like .Name, found "Files"`},
+		{"<box><if test=\".A\" bg=\"red\"/></box>", "1:6: bg: unknown attribute"},
+		{"<box\n padding=\"wide\"/>", `1:1: padding: expected a length in pixels, found "wide"`},
+		{"<box bg=\"#12\"/>", "1:1: bg: invalid color #12"},
+		{"<box style=\"margin: x\"/>", `1:1: style: margin: expected a length in pixels, found "x"`},
+		{"<box text=\"{{.Name\"/>", "1:1: text: template: :1: unclosed action"},
+		{"<box><if test=\".A\">text</if></box>", "1:20: text has to be inside a box"},
+		{"<box>a<box/>b</box>", "1:7: text can't be mixed with elements, put it in a box"},
+		{"<box><box/>b</box>", "1:12: text can't be mixed with elements, put it in a box"},
+		{"<box>\n<box>", "2:6: unexpected EOF"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.src)
+		var parseErr *gala.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q: error %v, want a *gala.ParseError", test.src, err)
+			continue
+		}
+		if err.Error() != test.want {
+			t.Errorf("%q:\n got %s\nwant %s", test.src, err, test.want)
+		}
+	}
+}
+
+type user struct {
+	Name  string
+	Files []string
+}
+
+func (u user) Admin() bool { return u.Name == "root" }
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		data any
+		want string
+		err  string
+	}{
+		{"text", `<box id="a" text="hi"><box id="b">there</box></box>`, nil, "a(hi)[b(there)]", ""},
+		{"text split by a comment", `<box id="a">one<!-- two -->three</box>`, nil, "a(one three)", ""},
+		{"templates", `<box id="{{.Name}}" text="{{len .Files}} files"/>`,
+			user{Name: "ann", Files: []string{"x", "y"}}, "ann(2 files)", ""},
+		{"each", `<box id="a"><each items=".Files"><box text="{{.}}"/></each></box>`,
+			user{Files: []string{"x", "y"}}, "a[(x) (y)]", ""},
+		{"if and unless", `<box id="a"><if test=".Admin"><box id="admin"/></if><unless test=".Admin"><box id="user"/></unless></box>`,
+			user{Name: "root"}, "a[admin]", ""},
+		{"maps and missing keys", `<box id="a"><if test=".Missing"><box id="b"/></if><box text="{{.Name}}"/></box>`,
+			map[string]any{"Name": "map"}, "a[(map)]", ""},
+		{"unknown field", `<box id="a"><each items=".Folders"><box/></each></box>`,
+			user{}, "a", "1:13: .Folders has no field Folders"},
+		{"not a slice", `<box id="a"><each items=".Name"><box/></each></box>`,
+			user{}, "a", "1:13: .Name is a string, not a slice"},
+		{"unknown handler", `<box id="a" onclick="open"/>`, nil, "a", `1:1: onclick: unknown handler "open"`},
+		{"template error", `<box id="a" text="{{.Name.Nope}}"/>`, user{}, "a",
+			"1:1: template: :1:7: executing \"\" at <.Name.Nope>: can't evaluate field Nope in type string"},
+	}
+	for _, test := range tests {
+		doc, err := Parse(test.src)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		layout := gala.NewLayout(800, 600, 64)
+		_, err = doc.Build(&layout, test.data, nil)
+		layout.End(testRenderer{})
+		root := layout.Snapshot()
+		if got := outline(root.Children[0]); got != test.want {
+			t.Errorf("%s: built %s, want %s", test.name, got, test.want)
+		}
+		if test.err == "" && err != nil || test.err != "" && (err == nil || err.Error() != test.err) {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+		}
+	}
+}
+
+func TestBuildStyles(t *testing.T) {
+	doc, err := Parse(`<box direction="column" bg="#fff" style="padding: 2px" radius="{{.}}"/>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout := gala.NewLayout(800, 600, 64)
+	doc.Build(&layout, 3, nil)
+	layout.End(testRenderer{})
+	want := "flex-direction: column; padding-top: 2px; padding-right: 2px; padding-bottom: 2px; " +
+		"padding-left: 2px; background-color: #ffffffff; border-radius: 3px"
+	if got := layout.Snapshot().Children[0].Style; got != want {
+		t.Errorf("style\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestWatchedBuildErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.xml")
+	if err := os.WriteFile(path, []byte(`<box text="{{.Name}}"/>`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	layout := gala.NewLayout(800, 600, 64)
+	w := Watch(&layout, path)
+	if err := w.Err(); err != nil {
+		t.Fatal(err)
+	}
+	// numbers don't have a name
+	w.Build(&layout, 1, nil)
+	layout.End(testRenderer{})
+	if err := w.Err(); err == nil || !strings.HasPrefix(err.Error(), path+":1:1: template") {
+		t.Errorf("error %v after a failed build, want it in the file", err)
+	}
+	w.Build(&layout, user{Name: "ann"}, nil)
+	layout.End(testRenderer{})
+	if err := w.Err(); err != nil {
+		t.Errorf("error %v after a build that worked", err)
+	}
+}
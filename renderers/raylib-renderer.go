@@ -1,6 +1,7 @@
 package renderers
 
 import (
+	"gala/gala"
 	"image/color"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -11,7 +12,97 @@ type RaylibRenderer struct{}
 func (r RaylibRenderer) DrawRect(x, y, width, height int32, col color.RGBA) {
 	rl.DrawRectangle(x, y, width, height, col)
 }
+func (r RaylibRenderer) DrawRoundedRect(x, y, width, height, radius int32, col color.RGBA) {
+	rect := rl.NewRectangle(float32(x), float32(y), float32(width), float32(height))
+	// raylib takes the radius relative to the shorter side
+	roundness := min(1, float32(radius*2)/float32(max(1, min(width, height))))
+	rl.DrawRectangleRounded(rect, roundness, int32(radius), col)
+}
+func (r RaylibRenderer) DrawBorder(x, y, width, height int32, border gala.Border) {
+	top, right := int32(border.Top.Width), int32(border.Right.Width)
+	bottom, left := int32(border.Bottom.Width), int32(border.Left.Width)
+	// top and bottom span the corners, left and right fit between them
+	drawSide(x, y, width, top, true, border.Top)
+	drawSide(x, y+height-bottom, width, bottom, true, border.Bottom)
+	drawSide(x, y+top, left, height-top-bottom, false, border.Left)
+	drawSide(x+width-right, y+top, right, height-top-bottom, false, border.Right)
+}
+
+// drawSide draws a side of a border along its length
+func drawSide(x, y, width, height int32, horizontal bool, side gala.BorderSide) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	thickness, length := height, width
+	if !horizontal {
+		thickness, length = width, height
+	}
+	dash, gap := length, int32(0)
+	switch side.Style {
+	case gala.BorderDashed:
+		dash, gap = thickness*3, thickness*2
+	case gala.BorderDotted:
+		dash, gap = thickness, thickness
+	}
+	for i := int32(0); i < length; i += dash + gap {
+		n := min(dash, length-i)
+		if horizontal {
+			rl.DrawRectangle(x+i, y, n, height, side.Color)
+		} else {
+			rl.DrawRectangle(x, y+i, width, n, side.Color)
+		}
+	}
+}
+func (r RaylibRenderer) DrawText(text string, x, y, fontSize int32, col color.RGBA) {
+	rl.DrawText(text, x, y, fontSize, col)
+}
+func (r RaylibRenderer) MeasureText(text string, fontSize int32) int32 {
+	return rl.MeasureText(text, fontSize)
+}
+func (r RaylibRenderer) BeginScissor(x, y, width, height int32) {
+	rl.BeginScissorMode(x, y, width, height)
+}
+func (r RaylibRenderer) EndScissor() {
+	rl.EndScissorMode()
+}
 func (r RaylibRenderer) MousePos() (int32, int32) {
 	return rl.GetMouseX(), rl.GetMouseY()
 
 }
+
+// gala key codes are the same as raylib's
+func (r RaylibRenderer) KeyDown(key gala.Key) bool {
+	return rl.IsKeyDown(int32(key))
+}
+func (r RaylibRenderer) NextKey() gala.Key {
+	return gala.Key(rl.GetKeyPressed())
+}
+func (r RaylibRenderer) KeyRepeated(key gala.Key) bool {
+	return rl.IsKeyPressedRepeat(int32(key))
+}
+func (r RaylibRenderer) NextChar() rune {
+	return rune(rl.GetCharPressed())
+}
+
+// gala mouse buttons are the same as raylib's
+func (r RaylibRenderer) MouseDown(button gala.MouseButton) bool {
+	return rl.IsMouseButtonDown(rl.MouseButton(button))
+}
+func (r RaylibRenderer) MousePressed(button gala.MouseButton) bool {
+	return rl.IsMouseButtonPressed(rl.MouseButton(button))
+}
+func (r RaylibRenderer) MouseWheel() float32 {
+	return rl.GetMouseWheelMove()
+}
+
+// gala gamepad buttons are the same as raylib's
+func (r RaylibRenderer) GamepadPressed(button gala.GamepadButton) bool {
+	return rl.IsGamepadAvailable(0) && rl.IsGamepadButtonPressed(0, int32(button))
+}
+
+func (r RaylibRenderer) ClipboardText() string {
+	return rl.GetClipboardText()
+}
+func (r RaylibRenderer) SetClipboardText(text string) {
+	rl.SetClipboardText(text)
+}
@@ -0,0 +1,21 @@
+package widgets
+
+import "gala/gala"
+
+// Button returns its box and whether it was activated this frame.
+func (ui *UI) Button(id, text string) (*gala.Box, bool) {
+	l, t := ui.Layout, ui.Theme()
+	button := l.Box().Id(id).Focusable().Padding(2)
+	activated := ui.activated(button.ID())
+
+	return button.
+		BackgroundColor(ui.ring(button.ID())).
+		Contains(
+			ui.label(text).
+				PaddingTop(t.Padding / 2).
+				PaddingBottom(t.Padding / 2).
+				PaddingLeft(t.Padding).
+				PaddingRight(t.Padding).
+				BackgroundColor(ui.surface(button.ID())),
+		), activated
+}
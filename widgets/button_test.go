@@ -0,0 +1,66 @@
+package widgets
+
+import (
+	"gala/gala"
+	"testing"
+)
+
+// presses the key in the next frame
+func press(key gala.Key) func(input *testInput, r *testRenderer, frame func()) {
+	return func(input *testInput, r *testRenderer, frame func()) {
+		input.keys = []gala.Key{key}
+	}
+}
+
+func TestButtonActivation(t *testing.T) {
+	tests := []struct {
+		name    string
+		focused bool
+		act     func(input *testInput, r *testRenderer, frame func())
+		want    int
+	}{
+		{"click", false, func(input *testInput, r *testRenderer, frame func()) {
+			click(input, r, 5, 5, frame)
+		}, 1},
+		{"click elsewhere", false, func(input *testInput, r *testRenderer, frame func()) {
+			click(input, r, 300, 300, frame)
+		}, 0},
+		{"released elsewhere", false, func(input *testInput, r *testRenderer, frame func()) {
+			r.mouseX, r.mouseY = 5, 5
+			input.mouseDown, input.mousePressed = true, true
+			frame()
+			r.mouseX, r.mouseY = 300, 300
+			input.mouseDown, input.mousePressed = false, false
+			frame()
+		}, 0},
+		{"Enter", true, press(gala.KeyEnter), 1},
+		{"Space", true, press(gala.KeySpace), 1},
+		{"Enter without focus", false, press(gala.KeyEnter), 0},
+	}
+	for _, test := range tests {
+		layout := gala.NewLayout(800, 600, 64)
+		input := &testInput{}
+		layout.UseInput(input)
+		ui := New(&layout, DefaultTheme)
+		r := &testRenderer{-1, -1}
+		activations := 0
+		frame := func() {
+			box, activated := ui.Button("save", "Save")
+			if activated {
+				activations++
+			}
+			if test.focused {
+				layout.Focus(box.ID())
+			}
+			layout.End(r)
+		}
+		frame()
+		frame()
+		test.act(input, r, frame)
+		frame()
+		frame()
+		if activations != test.want {
+			t.Errorf("%s: activated %d times, want %d", test.name, activations, test.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package widgets
+
+import "gala/gala"
+
+/*
+Checkbox flips checked when activated.
+It returns its box and whether it was activated this frame.
+*/
+func (ui *UI) Checkbox(id, text string, checked *bool) (*gala.Box, bool) {
+	l, t := ui.Layout, ui.Theme()
+	checkbox := l.Box().Id(id).Focusable().Padding(2).FlexDirection_Row()
+	activated := ui.activated(checkbox.ID())
+	if activated {
+		*checked = !*checked
+	}
+
+	size := float32(t.FontSize + 2)
+	mark := transparent
+	if *checked {
+		mark = t.Accent
+	}
+	return checkbox.
+		BackgroundColor(ui.ring(checkbox.ID())).
+		Contains(
+			l.Box().
+				Size(size, size).
+				Padding(3).
+				BackgroundColor(ui.surface(checkbox.ID())).
+				Contains(
+					l.Box().
+						Size(size-6, size-6).
+						BackgroundColor(mark),
+				),
+			ui.label(text).MarginLeft(t.Padding),
+		), activated
+}
+
+/*
+Toggle is a switch that flips on when activated.
+It returns its box and whether it was activated this frame.
+*/
+func (ui *UI) Toggle(id, text string, on *bool) (*gala.Box, bool) {
+	l, t := ui.Layout, ui.Theme()
+	toggle := l.Box().Id(id).Focusable().Padding(2).FlexDirection_Row()
+	activated := ui.activated(toggle.ID())
+	if activated {
+		*on = !*on
+	}
+
+	knob := float32(t.FontSize - 2)
+	track, offset := ui.surface(toggle.ID()), int16(0)
+	if *on {
+		track, offset = t.Accent, int16(knob)
+	}
+	return toggle.
+		BackgroundColor(ui.ring(toggle.ID())).
+		Contains(
+			l.Box().
+				Size(knob*2+4, knob+4).
+				Padding(2).
+				BackgroundColor(track).
+				Contains(
+					l.Box().
+						Size(knob, knob).
+						MarginLeft(offset).
+						BackgroundColor(t.Knob),
+				),
+			ui.label(text).MarginLeft(t.Padding),
+		), activated
+}
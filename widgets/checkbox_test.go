@@ -0,0 +1,54 @@
+package widgets
+
+import (
+	"gala/gala"
+	"testing"
+)
+
+func TestCheckboxAndToggle(t *testing.T) {
+	widgets := []struct {
+		name    string
+		declare func(ui *UI, on *bool) *gala.Box
+	}{
+		{"Checkbox", func(ui *UI, on *bool) *gala.Box {
+			box, _ := ui.Checkbox("check", "Check", on)
+			return box
+		}},
+		{"Toggle", func(ui *UI, on *bool) *gala.Box {
+			box, _ := ui.Toggle("toggle", "Toggle", on)
+			return box
+		}},
+	}
+	for _, widget := range widgets {
+		layout := gala.NewLayout(800, 600, 64)
+		input := &testInput{}
+		layout.UseInput(input)
+		ui := New(&layout, DefaultTheme)
+		r := &testRenderer{-1, -1}
+		on, focused := false, false
+		frame := func() {
+			box := widget.declare(ui, &on)
+			if focused {
+				layout.Focus(box.ID())
+			}
+			layout.End(r)
+		}
+		frame()
+
+		// the mark is inside, clicking it toggles the whole box
+		click(input, r, 8, 8, frame)
+		frame()
+		if !on {
+			t.Errorf("%s: not on after a click", widget.name)
+		}
+
+		focused = true
+		frame()
+		input.keys = []gala.Key{gala.KeySpace}
+		frame()
+		frame()
+		if on {
+			t.Errorf("%s: still on after Space", widget.name)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package widgets
+
+import (
+	"gala/gala"
+	"slices"
+	"strconv"
+)
+
+/*
+DockNode is a node of the tree a Dock lays its panels out with.
+A node with children splits its space between them, in a column when
+it's Vertical. A node without children shows its panels as tabs.
+
+The tree is changed in place when panels are docked somewhere else,
+and it can be saved and restored with encoding/json.
+*/
+type DockNode struct {
+	Vertical bool        `json:"vertical,omitempty"`
+	Children []*DockNode `json:"children,omitempty"`
+	Panels   []string    `json:"panels,omitempty"`
+	Selected string      `json:"selected,omitempty"`
+}
+
+type dockZone int8
+
+const (
+	dockCenter dockZone = iota
+	dockLeft
+	dockRight
+	dockTop
+	dockBottom
+)
+
+// how far the mouse has to move before a tab is dragged
+const dragThreshold = 8
+
+type dockLeaf struct {
+	node *DockNode
+	id   gala.ID
+}
+
+type dockState struct {
+	dragging       string // the panel
+	startX, startY int32
+	moved          bool
+	leaves         []dockLeaf // of the last frame
+}
+
+/*
+Dock shows the panels of the tree, split into panes of tabs.
+content builds the panel with the id, it's only called for the
+selected panel of every group of tabs.
+
+Dragging the tab of a panel onto another group of tabs docks it there.
+Dropped on the middle of a group it joins its tabs, dropped near an
+edge it gets a pane of its own on that side.
+It fills its parent by default.
+*/
+func (ui *UI) Dock(id string, root *DockNode, content func(panel string) *gala.Box) *gala.Box {
+	l := ui.Layout
+	dock := l.Box().Id(id).Size(gala.Percent(100), gala.Percent(100))
+	s := gala.State[dockState](l, dock.ID())
+	root.normalize()
+
+	x, y := l.MousePos()
+	if s.dragging != "" && !l.MouseDown(gala.MouseLeft) {
+		if target, zone, _, ok := s.dropTarget(l, x, y); ok && s.moved {
+			root.move(s.dragging, target, zone)
+			root.normalize()
+		}
+		s.dragging = ""
+	}
+
+	onDrag := func(panel string) {
+		if s.dragging != panel {
+			s.dragging, s.startX, s.startY, s.moved = panel, x, y, false
+		}
+		if abs(x-s.startX) > dragThreshold || abs(y-s.startY) > dragThreshold {
+			s.moved = true
+		}
+	}
+	// the targets are found with the rects of the last frame, before they're replaced
+	_, _, r, ok := s.dropTarget(l, x, y)
+	s.leaves = s.leaves[:0]
+	dock.Contains(ui.dockNode(s, root, "0", content, onDrag))
+
+	// shows where the panel would go, above the panels. It's an overlay,
+	// so the boxes declared after the dock keep their ids, and the pointer
+	// goes through it to the tabs under it.
+	if ok && s.moved && s.dragging != "" {
+		highlight := ui.Theme().Accent
+		highlight.A = 96
+		l.Box().
+			OverlayAt(r.X, r.Y).
+			PassThrough().
+			Size(float32(r.Width), float32(r.Height)).
+			BackgroundColor(highlight)
+	}
+	return dock
+}
+
+// dockNode builds the panes or the tabs of the node
+func (ui *UI) dockNode(s *dockState, node *DockNode, id string,
+	content func(panel string) *gala.Box, onDrag func(panel string)) *gala.Box {
+	if len(node.Children) == 0 {
+		tabs := make([]Tab, len(node.Panels))
+		for i, panel := range node.Panels {
+			tabs[i] = Tab{Id: panel, Title: panel}
+		}
+		box, _ := ui.tabs(id, tabs, &node.Selected, func(tab Tab) *gala.Box {
+			return content(tab.Id)
+		}, nil, onDrag)
+		box.Size(gala.Percent(100), gala.Percent(100))
+		s.leaves = append(s.leaves, dockLeaf{node, box.ID()})
+		return box
+	}
+	panes := make([]Pane, len(node.Children))
+	for i, child := range node.Children {
+		panes[i] = Pane{Box: ui.dockNode(s, child, strconv.Itoa(i), content, onDrag), Min: 50}
+	}
+	if node.Vertical {
+		return ui.VSplitPane(id, panes...)
+	}
+	return ui.SplitPane(id, panes...)
+}
+
+// dropTarget finds the group of tabs under the point, the zone of it the
+// point is in and the rect the panel would take there
+func (s *dockState) dropTarget(l *gala.Layout, x, y int32) (*DockNode, dockZone, gala.Rect, bool) {
+	for _, leaf := range s.leaves {
+		r, ok := l.Rect(leaf.id)
+		if !ok || !r.Contains(x, y) || r.Width == 0 || r.Height == 0 {
+			continue
+		}
+		fx := float32(x-int32(r.X)) / float32(r.Width)
+		fy := float32(y-int32(r.Y)) / float32(r.Height)
+		// the nearest edge, when it's within a quarter of the size
+		zone, nearest := dockCenter, float32(0.25)
+		edges := [...]struct {
+			zone     dockZone
+			distance float32
+		}{{dockLeft, fx}, {dockRight, 1 - fx}, {dockTop, fy}, {dockBottom, 1 - fy}}
+		for _, edge := range edges {
+			if edge.distance < nearest {
+				zone, nearest = edge.zone, edge.distance
+			}
+		}
+		half := r
+		switch zone {
+		case dockLeft:
+			half.Width /= 2
+		case dockRight:
+			half.Width /= 2
+			half.X += r.Width - half.Width
+		case dockTop:
+			half.Height /= 2
+		case dockBottom:
+			half.Height /= 2
+			half.Y += r.Height - half.Height
+		}
+		return leaf.node, zone, half, true
+	}
+	return nil, dockCenter, gala.Rect{}, false
+}
+
+// move docks the panel in the zone of the target, the tree
+// has to be normalized afterwards
+func (n *DockNode) move(panel string, target *DockNode, zone dockZone) {
+	source := n.leafOf(panel)
+	if source == nil || !n.contains(target) {
+		return
+	}
+	if source == target && (zone == dockCenter || len(source.Panels) == 1) {
+		return
+	}
+	source.Panels = slices.DeleteFunc(source.Panels, func(p string) bool { return p == panel })
+	if zone == dockCenter {
+		target.Panels = append(target.Panels, panel)
+		target.Selected = panel
+		return
+	}
+	// the target becomes a split of the old target and the new panel
+	leaf := &DockNode{Panels: []string{panel}, Selected: panel}
+	old := *target
+	*target = DockNode{Vertical: zone == dockTop || zone == dockBottom}
+	if zone == dockLeft || zone == dockTop {
+		target.Children = []*DockNode{leaf, &old}
+	} else {
+		target.Children = []*DockNode{&old, leaf}
+	}
+}
+
+// the group of tabs the panel is in
+func (n *DockNode) leafOf(panel string) *DockNode {
+	if slices.Contains(n.Panels, panel) {
+		return n
+	}
+	for _, child := range n.Children {
+		if leaf := child.leafOf(panel); leaf != nil {
+			return leaf
+		}
+	}
+	return nil
+}
+
+func (n *DockNode) contains(node *DockNode) bool {
+	if n == node {
+		return true
+	}
+	for _, child := range n.Children {
+		if child.contains(node) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize removes empty groups of tabs, replaces splits of a single node
+// with that node and merges splits into their parent when they go the same way
+func (n *DockNode) normalize() {
+	var children []*DockNode
+	for _, child := range n.Children {
+		child.normalize()
+		switch {
+		case len(child.Children) == 0 && len(child.Panels) == 0:
+		case len(child.Children) > 0 && child.Vertical == n.Vertical:
+			children = append(children, child.Children...)
+		default:
+			children = append(children, child)
+		}
+	}
+	n.Children = children
+	if len(n.Children) == 1 {
+		*n = *n.Children[0]
+	}
+	if len(n.Children) > 0 {
+		n.Panels, n.Selected = nil, ""
+	}
+}
+
+func abs(i int32) int32 {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,126 @@
+package widgets
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// dock parses a tree written as JSON, to keep the tests short
+func dock(t *testing.T, src string) *DockNode {
+	t.Helper()
+	var n DockNode
+	if err := json.Unmarshal([]byte(src), &n); err != nil {
+		t.Fatal(err)
+	}
+	return &n
+}
+
+func encode(n *DockNode) string {
+	data, _ := json.Marshal(n)
+	return string(data)
+}
+
+func TestDockMove(t *testing.T) {
+	tests := []struct {
+		name   string
+		tree   string
+		panel  string
+		target []int // the path of the target from the root
+		zone   dockZone
+		want   string
+	}{
+		{
+			"into the tabs of another group",
+			`{"children":[{"panels":["A","B"]},{"panels":["C"]}]}`,
+			"B", []int{1}, dockCenter,
+			`{"children":[{"panels":["A"]},{"panels":["C","B"],"selected":"B"}]}`,
+		},
+		{
+			"the last panel of a group",
+			`{"children":[{"panels":["A"]},{"panels":["C"]}]}`,
+			"A", []int{1}, dockCenter,
+			`{"panels":["C","A"],"selected":"A"}`,
+		},
+		{
+			"beside a group the same way",
+			`{"children":[{"panels":["A","B"]},{"panels":["C"]}]}`,
+			"B", []int{1}, dockRight,
+			`{"children":[{"panels":["A"]},{"panels":["C"]},{"panels":["B"],"selected":"B"}]}`,
+		},
+		{
+			"above a group",
+			`{"children":[{"panels":["A","B"]},{"panels":["C"]}]}`,
+			"B", []int{1}, dockTop,
+			`{"children":[{"panels":["A"]},{"vertical":true,"children":[{"panels":["B"],"selected":"B"},{"panels":["C"]}]}]}`,
+		},
+		{
+			"splits its own group",
+			`{"panels":["A","B"]}`,
+			"B", nil, dockLeft,
+			`{"children":[{"panels":["B"],"selected":"B"},{"panels":["A"]}]}`,
+		},
+		{
+			"alone in its group onto itself",
+			`{"children":[{"panels":["A"]},{"panels":["C"]}]}`,
+			"A", []int{0}, dockBottom,
+			`{"children":[{"panels":["A"]},{"panels":["C"]}]}`,
+		},
+		{
+			"a panel that isn't docked",
+			`{"children":[{"panels":["A"]},{"panels":["C"]}]}`,
+			"X", []int{0}, dockCenter,
+			`{"children":[{"panels":["A"]},{"panels":["C"]}]}`,
+		},
+	}
+	for _, test := range tests {
+		root := dock(t, test.tree)
+		target := root
+		for _, i := range test.target {
+			target = target.Children[i]
+		}
+		root.move(test.panel, target, test.zone)
+		root.normalize()
+		if got := encode(root); got != test.want {
+			t.Errorf("%s:\n got %s\nwant %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDockNormalize(t *testing.T) {
+	tests := []struct {
+		name, tree, want string
+	}{
+		{
+			"empty groups are removed",
+			`{"children":[{"panels":["A"]},{},{"panels":["C"]}]}`,
+			`{"children":[{"panels":["A"]},{"panels":["C"]}]}`,
+		},
+		{
+			"a split of one node is that node",
+			`{"vertical":true,"children":[{"panels":["A"],"selected":"A"}]}`,
+			`{"panels":["A"],"selected":"A"}`,
+		},
+		{
+			"splits the same way are merged",
+			`{"children":[{"panels":["A"]},{"children":[{"panels":["B"]},{"panels":["C"]}]}]}`,
+			`{"children":[{"panels":["A"]},{"panels":["B"]},{"panels":["C"]}]}`,
+		},
+		{
+			"splits across are kept",
+			`{"children":[{"panels":["A"]},{"vertical":true,"children":[{"panels":["B"]},{"panels":["C"]}]}]}`,
+			`{"children":[{"panels":["A"]},{"vertical":true,"children":[{"panels":["B"]},{"panels":["C"]}]}]}`,
+		},
+		{
+			"nested splits that end up alone",
+			`{"children":[{"vertical":true,"children":[{},{"panels":["B"]}]},{}]}`,
+			`{"panels":["B"]}`,
+		},
+	}
+	for _, test := range tests {
+		root := dock(t, test.tree)
+		root.normalize()
+		if got := encode(root); got != test.want {
+			t.Errorf("%s:\n got %s\nwant %s", test.name, got, test.want)
+		}
+	}
+}
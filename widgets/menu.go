@@ -0,0 +1,417 @@
+package widgets
+
+import (
+	"gala/gala"
+	"strconv"
+	"time"
+)
+
+// how long a submenu stays open while the mouse moves toward it over other items
+const submenuDelay = 300 * time.Millisecond
+
+// Shortcut is a key with the modifiers that have to be held with it.
+type Shortcut struct {
+	Key     gala.Key
+	Control bool
+	Shift   bool
+}
+
+// String returns the shortcut like "Ctrl+Shift+S", or "" when it has no key.
+func (s Shortcut) String() string {
+	if s.Key == gala.KeyNull {
+		return ""
+	}
+	text := s.Key.String()
+	if s.Shift {
+		text = "Shift+" + text
+	}
+	if s.Control {
+		text = "Ctrl+" + text
+	}
+	return text
+}
+
+// pressed reports whether the shortcut was pressed this frame and consumes it
+func (s Shortcut) pressed(l *gala.Layout) bool {
+	if s.Key == gala.KeyNull || !l.KeyPressed(s.Key) ||
+		l.ControlDown() != s.Control || l.ShiftDown() != s.Shift {
+		return false
+	}
+	l.ConsumeKey(s.Key)
+	return true
+}
+
+// MenuItem of a menu. Items without a Text are separators and
+// items with Items open them as a submenu.
+type MenuItem struct {
+	Text     string
+	Shortcut Shortcut
+	Disabled bool
+	Action   func()
+	Items    []MenuItem
+}
+
+// Menu of a MenuBar.
+type Menu struct {
+	Text  string
+	Items []MenuItem
+}
+
+type menuState struct {
+	open      bool
+	top       int   // the open menu of a menu bar
+	path      []int // the items whose submenus are open, by level
+	highlight int   // in the deepest open menu, -1 for none
+	lists     []gala.ID
+	x, y      int32 // where a context menu was opened
+
+	// where the mouse was on the item of the deepest submenu,
+	// the submenu is kept open while it moves toward it from there
+	apexX, apexY int32
+	apexAt       time.Time
+}
+
+func (s *menuState) close() {
+	s.open, s.path, s.highlight = false, s.path[:0], -1
+}
+
+// fit cuts the path and the highlight down to the items, they may have changed
+// since the last frame, and returns the items of the deepest open menu
+func (s *menuState) fit(root []MenuItem) []MenuItem {
+	items := root
+	for level, i := range s.path {
+		if i >= len(items) || len(items[i].Items) == 0 {
+			s.path, s.highlight = s.path[:level], -1
+			break
+		}
+		items = items[i].Items
+	}
+	if s.highlight >= len(items) {
+		s.highlight = -1
+	}
+	return items
+}
+
+// opens the submenu of the highlighted item, highlighting its first item
+func (s *menuState) openSubmenu(items []MenuItem) {
+	s.path, s.highlight = append(s.path, s.highlight), -1
+	for i, item := range items {
+		if item.Text != "" && !item.Disabled {
+			s.highlight = i
+			break
+		}
+	}
+}
+
+/*
+MenuBar shows a row of menus that drop down when clicked, their items open
+submenus when hovered. While a menu is open the arrow keys move between
+its items and menus, Enter picks an item and Escape closes the menu.
+
+The shortcuts of the items are shown next to them and run their actions
+from anywhere, they take the keys before the focused box gets them.
+*/
+func (ui *UI) MenuBar(id string, menus []Menu) *gala.Box {
+	l, t := ui.Layout, ui.Theme()
+	bar := l.Box().Id(id).FlexDirection_Row().Width(gala.Percent(100)).BackgroundColor(t.Surface)
+	s := gala.State[menuState](l, bar.ID())
+	for _, menu := range menus {
+		ui.dispatchShortcuts(menu.Items)
+	}
+	s.top = max(0, min(s.top, len(menus)-1))
+	if s.open && len(menus) > 0 {
+		switch ui.menuKeys(s, menus[s.top].Items) {
+		case gala.KeyLeft:
+			s.top = (s.top - 1 + len(menus)) % len(menus)
+		case gala.KeyRight:
+			s.top = (s.top + 1) % len(menus)
+		}
+	}
+
+	overBar := false
+	labels := make([]*gala.Box, len(menus))
+	for i, menu := range menus {
+		label := ui.label(menu.Text).
+			Id(id + "/" + strconv.Itoa(i)).
+			PaddingTop(t.Padding / 2).
+			PaddingBottom(t.Padding / 2).
+			PaddingLeft(t.Padding).
+			PaddingRight(t.Padding)
+		bar.Contains(label)
+		labels[i] = label
+		hovered := l.Hovered(label.ID())
+		overBar = overBar || hovered
+		switch {
+		case hovered && l.MousePressed(gala.MouseLeft):
+			if s.open && s.top == i {
+				s.close()
+			} else {
+				s.open, s.top, s.path, s.highlight = true, i, s.path[:0], -1
+			}
+		case hovered && s.open && s.top != i:
+			s.top, s.path, s.highlight = i, s.path[:0], -1
+		}
+	}
+	if !s.open || len(menus) == 0 {
+		return bar
+	}
+	labels[s.top].BackgroundColor(t.Accent)
+	overMenus := ui.menu(s, id, menus[s.top].Items, labels[s.top], gala.SideBottom, 0)
+	if l.MousePressed(gala.MouseLeft) && !overMenus && !overBar {
+		s.close()
+	}
+	return bar
+}
+
+/*
+ContextMenu opens a menu at the mouse when the target is right clicked.
+It works like the menus of a MenuBar, but the shortcuts of its items
+aren't dispatched. It returns the target.
+*/
+func (ui *UI) ContextMenu(id string, target *gala.Box, items []MenuItem) *gala.Box {
+	l := ui.Layout
+	s := gala.State[menuState](l, target.ID())
+	x, y := l.MousePos()
+	if l.MousePressed(gala.MouseRight) {
+		s.close()
+		if l.Hovered(target.ID()) {
+			s.open, s.x, s.y = true, x, y
+		}
+	}
+	if !s.open {
+		return target
+	}
+	ui.menuKeys(s, items)
+	if !s.open {
+		return target
+	}
+	// an empty overlay at the mouse for the menu to open next to,
+	// it comes after the other boxes so it doesn't change their ids
+	anchor := l.Box().
+		Id(id+"/anchor").
+		OverlayAt(int16(s.x), int16(s.y)).
+		PassThrough().
+		Size(0, 1)
+	overMenus := ui.menu(s, id, items, anchor, gala.SideRight, 0)
+	if l.MousePressed(gala.MouseLeft) && !overMenus {
+		s.close()
+	}
+	return target
+}
+
+// menu builds the open menu of the level and the open submenus in it,
+// it reports whether the mouse is over any of them
+func (ui *UI) menu(s *menuState, id string, items []MenuItem, anchor *gala.Box, side gala.Side, level int) bool {
+	l, t := ui.Layout, ui.Theme()
+	listId := id + "/menu" + strconv.Itoa(level)
+	list := l.Box().
+		Id(listId).
+		Overlay(anchor, side).
+		FlexDirection_Column().
+		Padding(2).
+		BackgroundColor(t.Surface)
+	for len(s.lists) <= level {
+		s.lists = append(s.lists, 0)
+	}
+	s.lists[level] = list.ID()
+	over := l.Hovered(list.ID())
+	width := ui.menuWidth(items)
+
+	rows := make([]*gala.Box, len(items))
+	for i, item := range items {
+		if item.Text == "" {
+			list.Contains(l.Box().Size(width, 1).MarginTop(2).MarginBottom(2).BackgroundColor(t.SurfaceHovered))
+			continue
+		}
+		row := l.Box().Id(listId + "/" + strconv.Itoa(i)).FlexDirection_Row().Width(width)
+		rows[i] = row
+		labels := []*gala.Box{ui.label(item.Text).Flex(1).PaddingLeft(t.Padding).PaddingRight(t.Padding)}
+		if shortcut := item.Shortcut.String(); shortcut != "" {
+			labels = append(labels, ui.label(shortcut).PaddingRight(t.Padding))
+		}
+		if len(item.Items) > 0 {
+			labels = append(labels, ui.label(">").PaddingRight(t.Padding/2))
+		}
+		for _, label := range labels {
+			label.PaddingTop(t.Padding / 4).PaddingBottom(t.Padding / 4)
+			if item.Disabled {
+				dimmed := t.Text
+				dimmed.A /= 2
+				label.TextColor(dimmed)
+			}
+			row.Contains(label)
+		}
+		list.Contains(row)
+
+		if l.Hovered(row.ID()) && !item.Disabled {
+			ui.hoverMenuItem(s, level, i, item)
+		}
+		if l.Clicked(row.ID()) && !item.Disabled && item.Action != nil {
+			item.Action()
+			s.close()
+			list.Display_None()
+			return true
+		}
+		if level < len(s.path) && s.path[level] == i || level == len(s.path) && s.highlight == i {
+			row.BackgroundColor(t.Accent)
+		}
+	}
+	if level < len(s.path) && s.path[level] < len(items) {
+		open := s.path[level]
+		over = ui.menu(s, id, items[open].Items, rows[open], gala.SideRight, level+1) || over
+	}
+	return over
+}
+
+// hoverMenuItem highlights the item under the mouse and opens its submenu,
+// unless the mouse is on its way to the open submenu of another item
+func (ui *UI) hoverMenuItem(s *menuState, level, i int, item MenuItem) {
+	l := ui.Layout
+	x, y := l.MousePos()
+	if level < len(s.path) && s.path[level] == i {
+		if len(s.path) > level+1 {
+			s.path, s.highlight = s.path[:level+1], s.path[level+1]
+		}
+		s.apexX, s.apexY, s.apexAt = x, y, l.Now()
+		return
+	}
+	if level < len(s.path) && ui.towardSubmenu(s, level+1, x, y) {
+		return
+	}
+	s.path, s.highlight = s.path[:level], i
+	if len(item.Items) > 0 {
+		s.path, s.highlight = append(s.path, i), -1
+		s.apexX, s.apexY, s.apexAt = x, y, l.Now()
+	}
+}
+
+// towardSubmenu reports whether the point is in the triangle between
+// where the mouse left the item of the submenu and the near edge of it
+func (ui *UI) towardSubmenu(s *menuState, level int, x, y int32) bool {
+	l := ui.Layout
+	if level >= len(s.lists) || l.Now().Sub(s.apexAt) > submenuDelay {
+		return false
+	}
+	r, ok := l.Rect(s.lists[level])
+	if !ok {
+		return false
+	}
+	edge := int32(r.X)
+	if edge < s.apexX {
+		edge = int32(r.Right())
+	}
+	return inTriangle(x, y, s.apexX, s.apexY, edge, int32(r.Y), edge, int32(r.Bottom()))
+}
+
+func inTriangle(x, y, ax, ay, bx, by, cx, cy int32) bool {
+	side := func(x1, y1, x2, y2 int32) int64 {
+		return int64(x2-x1)*int64(y-y1) - int64(y2-y1)*int64(x-x1)
+	}
+	d1, d2, d3 := side(ax, ay, bx, by), side(bx, by, cx, cy), side(cx, cy, ax, ay)
+	negative := d1 < 0 || d2 < 0 || d3 < 0
+	positive := d1 > 0 || d2 > 0 || d3 > 0
+	return !(negative && positive)
+}
+
+// menuKeys handles the keyboard while the menu is open. It returns
+// Left or Right when they would move past the menu, for a menu bar.
+func (ui *UI) menuKeys(s *menuState, root []MenuItem) gala.Key {
+	l := ui.Layout
+	items := s.fit(root)
+	selectable := func(i int) bool {
+		return items[i].Text != "" && !items[i].Disabled
+	}
+	consume := func(key gala.Key) bool {
+		if l.KeyPressed(key) {
+			l.ConsumeKey(key)
+			return true
+		}
+		return false
+	}
+	step := func(from, delta int) int {
+		for i := range items {
+			next := ((from+delta*(i+1))%len(items) + len(items)) % len(items)
+			if selectable(next) {
+				return next
+			}
+		}
+		return -1
+	}
+	if len(items) == 0 {
+		if consume(gala.KeyEscape) {
+			s.close()
+		}
+		return gala.KeyNull
+	}
+	switch {
+	case consume(gala.KeyDown):
+		s.highlight = step(max(-1, s.highlight), 1)
+	case consume(gala.KeyUp):
+		if s.highlight < 0 {
+			s.highlight = 0
+		}
+		s.highlight = step(s.highlight, -1)
+	case consume(gala.KeyRight):
+		if s.highlight >= 0 && len(items[s.highlight].Items) > 0 && selectable(s.highlight) {
+			s.openSubmenu(items[s.highlight].Items)
+			return gala.KeyNull
+		}
+		return gala.KeyRight
+	case consume(gala.KeyLeft):
+		if len(s.path) > 0 {
+			s.highlight, s.path = s.path[len(s.path)-1], s.path[:len(s.path)-1]
+			return gala.KeyNull
+		}
+		return gala.KeyLeft
+	case consume(gala.KeyEscape):
+		if len(s.path) > 0 {
+			s.highlight, s.path = s.path[len(s.path)-1], s.path[:len(s.path)-1]
+		} else {
+			s.close()
+		}
+	case consume(gala.KeyEnter) || consume(gala.KeySpace):
+		if s.highlight < 0 || !selectable(s.highlight) {
+			break
+		}
+		item := items[s.highlight]
+		if len(item.Items) > 0 {
+			s.openSubmenu(item.Items)
+		} else {
+			if item.Action != nil {
+				item.Action()
+			}
+			s.close()
+		}
+	}
+	return gala.KeyNull
+}
+
+// menuWidth fits the widest item with its shortcut and arrow
+func (ui *UI) menuWidth(items []MenuItem) float32 {
+	l, t := ui.Layout, ui.Theme()
+	var width int16
+	for _, item := range items {
+		w := l.MeasureText(item.Text, t.FontSize) + t.Padding*2
+		if shortcut := item.Shortcut.String(); shortcut != "" {
+			w += l.MeasureText(shortcut, t.FontSize) + t.Padding
+		}
+		if len(item.Items) > 0 {
+			w += l.MeasureText(">", t.FontSize) + t.Padding/2
+		}
+		width = max(width, w)
+	}
+	return float32(width)
+}
+
+// dispatchShortcuts runs the actions of the items whose shortcut was pressed
+func (ui *UI) dispatchShortcuts(items []MenuItem) {
+	for _, item := range items {
+		if item.Disabled {
+			continue
+		}
+		if item.Action != nil && item.Shortcut.pressed(ui.Layout) {
+			item.Action()
+		}
+		ui.dispatchShortcuts(item.Items)
+	}
+}
@@ -0,0 +1,89 @@
+package widgets
+
+import (
+	"gala/gala"
+	"testing"
+)
+
+func TestMenuItemsShrink(t *testing.T) {
+	layout := gala.NewLayout(800, 600, 128)
+	input := &testInput{}
+	layout.UseInput(input)
+	ui := New(&layout, DefaultTheme)
+	item := func(text string) MenuItem { return MenuItem{Text: text} }
+	submenu := MenuItem{Text: "More", Items: []MenuItem{item("a"), item("b"), item("c")}}
+
+	tests := []struct {
+		name      string
+		items     []MenuItem
+		path      []int
+		highlight int
+	}{
+		{"the open submenu is gone", []MenuItem{item("One")}, nil, -1},
+		{"the open item has no submenu anymore", []MenuItem{item("One"), item("More")}, nil, -1},
+		{"the submenu is shorter", []MenuItem{item("One"), {Text: "More", Items: []MenuItem{item("a")}}}, []int{1}, -1},
+		{"the highlighted item is picked and closes the menu", []MenuItem{item("One"), submenu}, nil, -1},
+	}
+	for _, test := range tests {
+		var bar *gala.Box
+		frame := func(items []MenuItem) {
+			bar = ui.MenuBar("menu", []Menu{{Text: "File", Items: items}})
+			layout.End(testRenderer{-1, -1})
+		}
+		frame([]MenuItem{item("One"), submenu})
+		s := gala.State[menuState](&layout, bar.ID())
+		s.open, s.path, s.highlight = true, []int{1}, 2
+
+		// Enter picks the highlighted item, of the items of this frame
+		input.keys = []gala.Key{gala.KeyEnter}
+		frame(test.items)
+		if len(s.path) != len(test.path) || s.highlight != test.highlight {
+			t.Errorf("%s: path %v, highlight %d, want %v, %d", test.name, s.path, s.highlight, test.path, test.highlight)
+		}
+		s.close()
+		frame(test.items)
+	}
+}
+
+func TestContextMenu(t *testing.T) {
+	layout := gala.NewLayout(800, 600, 128)
+	input := &testInput{}
+	layout.UseInput(input)
+	ui := New(&layout, DefaultTheme)
+	r := &testRenderer{-1, -1}
+	copied := 0
+	items := []MenuItem{{Text: "Copy", Action: func() { copied++ }}}
+	frame := func() {
+		area := ui.ContextMenu("context", layout.Box().Id("area").Size(200, 200), items)
+		after, _ := ui.Button("after", "After")
+		layout.Box().FlexDirection_Column().Contains(area, after)
+		layout.End(r)
+	}
+	byId := func(id string) (gala.Snapshot, bool) {
+		return find(layout.Snapshot(), func(s gala.Snapshot) bool { return s.Id == id })
+	}
+	r.mouseX, r.mouseY = 50, 60
+	frame()
+	closed, _ := byId("after")
+
+	input.rightPressed = true
+	frame()
+	input.rightPressed = false
+	list, ok := byId("context/menu0")
+	if !ok {
+		t.Fatal("no menu after a right click on the target")
+	}
+	if list.X != 50 || list.Y != 60 {
+		t.Errorf("the menu is at %d, %d, want it at the mouse, 50, 60", list.X, list.Y)
+	}
+	if open, _ := byId("after"); open.Hash != closed.Hash {
+		t.Errorf("the box after the target has the id %d while the menu is open, want %d", open.Hash, closed.Hash)
+	}
+
+	item, _ := byId("context/menu0/0")
+	click(input, r, int32(item.X+item.Width/2), int32(item.Y+item.Height/2), frame)
+	frame()
+	if copied != 1 {
+		t.Errorf("the action ran %d times after clicking the item, want once", copied)
+	}
+}
@@ -0,0 +1,59 @@
+package widgets
+
+import (
+	"gala/gala"
+	"strconv"
+)
+
+/*
+RadioGroup lets one of the options be selected, selected is its index.
+
+The group is a single stop in the Tab order, while it has focus
+the arrow keys move the selection.
+It returns its box and whether the selection changed this frame.
+*/
+func (ui *UI) RadioGroup(id string, options []string, selected *int) (*gala.Box, bool) {
+	l, t := ui.Layout, ui.Theme()
+	group := l.Box().Id(id).Focusable().Padding(2).FlexDirection_Column()
+	previous := *selected
+
+	if l.Focused(group.ID()) && len(options) > 0 {
+		for _, key := range [...]gala.Key{gala.KeyUp, gala.KeyLeft, gala.KeyDown, gala.KeyRight} {
+			if !l.KeyPressed(key) {
+				continue
+			}
+			l.ConsumeKey(key)
+			if key == gala.KeyUp || key == gala.KeyLeft {
+				*selected = max(0, *selected-1)
+			} else {
+				*selected = min(len(options)-1, *selected+1)
+			}
+		}
+	}
+
+	size := float32(t.FontSize + 2)
+	for i, option := range options {
+		row := l.Box().Id(id + "/" + strconv.Itoa(i)).FlexDirection_Row().MarginTop(t.Padding / 4).MarginBottom(t.Padding / 4)
+		group.Contains(row)
+		if l.Clicked(row.ID()) {
+			*selected = i
+		}
+		dot := transparent
+		if i == *selected {
+			dot = t.Accent
+		}
+		row.Contains(
+			l.Box().
+				Size(size, size).
+				Padding(4).
+				BackgroundColor(ui.surface(row.ID())).
+				Contains(
+					l.Box().
+						Size(size-8, size-8).
+						BackgroundColor(dot),
+				),
+			ui.label(option).MarginLeft(t.Padding),
+		)
+	}
+	return group.BackgroundColor(ui.ring(group.ID())), *selected != previous
+}
@@ -0,0 +1,68 @@
+package widgets
+
+import (
+	"gala/gala"
+	"testing"
+)
+
+func TestRadioGroup(t *testing.T) {
+	layout := gala.NewLayout(800, 600, 64)
+	input := &testInput{}
+	layout.UseInput(input)
+	ui := New(&layout, DefaultTheme)
+	r := &testRenderer{-1, -1}
+	options := []string{"a", "b", "c"}
+	selected, extra, focused := 0, false, false
+	frame := func() {
+		if extra {
+			// declared before the group, so the pool slots shift
+			layout.Box().Size(10, 10)
+		}
+		group, _ := ui.RadioGroup("group", options, &selected)
+		if focused {
+			layout.Focus(group.ID())
+		}
+		layout.End(r)
+	}
+	frame()
+	center := func(text string) (int32, int32) {
+		label, ok := find(layout.Snapshot(), func(s gala.Snapshot) bool { return s.Text == text })
+		if !ok {
+			t.Fatalf("no label %q", text)
+		}
+		return int32(label.X + label.Width/2), int32(label.Y + label.Height/2)
+	}
+
+	x, y := center("b")
+	click(input, r, x, y, frame)
+	frame()
+	if selected != 1 {
+		t.Errorf("selected %d after clicking b, want 1", selected)
+	}
+
+	// the click is read in the frame after the release, which declares a box more
+	x, y = center("c")
+	r.mouseX, r.mouseY = x, y
+	input.mouseDown, input.mousePressed = true, true
+	frame()
+	input.mouseDown, input.mousePressed = false, false
+	frame()
+	extra = true
+	frame()
+	if selected != 2 {
+		t.Errorf("selected %d after clicking c while the tree shifted, want 2", selected)
+	}
+
+	focused = true
+	frame()
+	input.keys = []gala.Key{gala.KeyUp}
+	frame()
+	if selected != 1 {
+		t.Errorf("selected %d after Up, want 1", selected)
+	}
+	input.keys = []gala.Key{gala.KeyDown, gala.KeyDown}
+	frame()
+	if selected != 2 {
+		t.Errorf("selected %d after Down twice, want 2", selected)
+	}
+}
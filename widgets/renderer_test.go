@@ -0,0 +1,80 @@
+package widgets
+
+import (
+	"gala/gala"
+	"image/color"
+	"unicode/utf8"
+)
+
+// testRenderer draws nothing, every character is half the font size wide.
+// Tests that don't use the mouse put it off the screen, at -1, -1.
+type testRenderer struct {
+	mouseX, mouseY int32
+}
+
+func (testRenderer) DrawRect(posX, posY, width, height int32, color color.RGBA)     {}
+func (testRenderer) DrawBorder(posX, posY, width, height int32, border gala.Border) {}
+func (testRenderer) DrawText(text string, posX, posY, fontSize int32, c color.RGBA) {}
+func (testRenderer) BeginScissor(posX, posY, width, height int32)                   {}
+func (testRenderer) EndScissor()                                                    {}
+
+func (testRenderer) MeasureText(text string, fontSize int32) int32 {
+	return int32(utf8.RuneCountInString(text)) * fontSize / 2
+}
+
+func (r testRenderer) MousePos() (x int32, y int32) {
+	return r.mouseX, r.mouseY
+}
+
+// find returns the first box of the snapshot, depth first, that matches
+func find(s gala.Snapshot, match func(gala.Snapshot) bool) (gala.Snapshot, bool) {
+	if match(s) {
+		return s, true
+	}
+	for _, child := range s.Children {
+		if found, ok := find(child, match); ok {
+			return found, true
+		}
+	}
+	return gala.Snapshot{}, false
+}
+
+// testInput presses the keys in the next frame, once,
+// and holds the left mouse button while mouseDown is set
+type testInput struct {
+	keys         []gala.Key
+	mouseDown    bool
+	mousePressed bool // the left button went down this frame
+	rightPressed bool // the right button went down this frame
+}
+
+func (i *testInput) NextKey() gala.Key {
+	if len(i.keys) == 0 {
+		return gala.KeyNull
+	}
+	key := i.keys[0]
+	i.keys = i.keys[1:]
+	return key
+}
+
+func (i *testInput) KeyDown(key gala.Key) bool     { return false }
+func (i *testInput) KeyRepeated(key gala.Key) bool { return false }
+func (i *testInput) NextChar() rune                { return 0 }
+func (i *testInput) MouseDown(button gala.MouseButton) bool {
+	return button == gala.MouseLeft && i.mouseDown
+}
+func (i *testInput) MousePressed(button gala.MouseButton) bool {
+	return button == gala.MouseLeft && i.mousePressed || button == gala.MouseRight && i.rightPressed
+}
+func (i *testInput) MouseWheel() float32                           { return 0 }
+func (i *testInput) GamepadPressed(button gala.GamepadButton) bool { return false }
+
+// click presses the left button over the point in one frame and releases
+// it in the next one, frame declares the boxes and ends the frame
+func click(input *testInput, r *testRenderer, x, y int32, frame func()) {
+	r.mouseX, r.mouseY = x, y
+	input.mouseDown, input.mousePressed = true, true
+	frame()
+	input.mouseDown, input.mousePressed = false, false
+	frame()
+}
@@ -0,0 +1,107 @@
+package widgets
+
+import (
+	"gala/gala"
+	"strconv"
+)
+
+type selectState struct {
+	open        bool
+	highlighted int
+	list        gala.ID // the id of the open list, for clicks outside of it
+}
+
+/*
+Select shows the selected option and opens a list of all of them when
+activated. The list is an overlay, so it isn't clipped by scrolling parents.
+
+While open, the arrow keys move the highlight, Enter picks it and Escape
+or clicking outside closes the list.
+It returns its box and whether the selection changed this frame.
+*/
+func (ui *UI) Select(id string, options []string, selected *int) (*gala.Box, bool) {
+	l, t := ui.Layout, ui.Theme()
+	field := l.Box().Id(id).Focusable().Padding(2)
+	s := gala.State[selectState](l, field.ID())
+	previous := *selected
+
+	focused := l.Focused(field.ID())
+	if !focused {
+		s.open = false
+	}
+	if s.open {
+		if l.KeyPressed(gala.KeyUp) {
+			l.ConsumeKey(gala.KeyUp)
+			s.highlighted = max(0, s.highlighted-1)
+		}
+		if l.KeyPressed(gala.KeyDown) {
+			l.ConsumeKey(gala.KeyDown)
+			s.highlighted = min(len(options)-1, s.highlighted+1)
+		}
+		if l.KeyPressed(gala.KeyEscape) {
+			l.ConsumeKey(gala.KeyEscape)
+			s.open = false
+		}
+		if l.KeyPressed(gala.KeyEnter) {
+			l.ConsumeKey(gala.KeyEnter)
+			*selected, s.open = s.highlighted, false
+		}
+		// clicking the field again or anywhere but the list closes it
+		if l.Clicked(field.ID()) ||
+			l.MousePressed(gala.MouseLeft) && !l.Hovered(field.ID()) && !l.Hovered(s.list) {
+			s.open = false
+		}
+	} else if ui.activated(field.ID()) {
+		s.open, s.highlighted = true, *selected
+	}
+
+	current := ""
+	if *selected >= 0 && *selected < len(options) {
+		current = options[*selected]
+	}
+	field.
+		BackgroundColor(ui.ring(field.ID())).
+		Contains(
+			ui.label(current + "  v").
+				PaddingTop(t.Padding / 2).
+				PaddingBottom(t.Padding / 2).
+				PaddingLeft(t.Padding).
+				PaddingRight(t.Padding).
+				BackgroundColor(ui.surface(field.ID())),
+		)
+	if !s.open {
+		return field, *selected != previous
+	}
+
+	// declared only while open, overlays come after the other boxes
+	// so the ids of the boxes declared after this one don't change
+	list := l.Box().
+		Id(id+"/options").
+		Overlay(field, gala.SideBottom).
+		FlexDirection_Column().
+		BackgroundColor(t.Surface)
+	s.list = list.ID()
+	if r, ok := l.Rect(field.ID()); ok {
+		list.Width(float32(r.Width))
+	}
+	for i, option := range options {
+		row := ui.label(option).
+			Id(id + "/" + strconv.Itoa(i)).
+			Width(gala.Percent(100)).
+			PaddingTop(t.Padding / 2).
+			PaddingBottom(t.Padding / 2).
+			PaddingLeft(t.Padding).
+			PaddingRight(t.Padding)
+		list.Contains(row)
+		if l.Hovered(row.ID()) {
+			s.highlighted = i
+		}
+		if l.Clicked(row.ID()) {
+			*selected, s.open = i, false
+		}
+		if i == s.highlighted {
+			row.BackgroundColor(t.SurfaceHovered)
+		}
+	}
+	return field, *selected != previous
+}
@@ -0,0 +1,50 @@
+package widgets
+
+import (
+	"gala/gala"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	layout := gala.NewLayout(800, 600, 64)
+	input := &testInput{}
+	layout.UseInput(input)
+	ui := New(&layout, DefaultTheme)
+	r := &testRenderer{-1, -1}
+	selected := 0
+	frame := func() {
+		field, _ := ui.Select("fruit", []string{"apple", "pear"}, &selected)
+		after, _ := ui.Button("after", "After")
+		layout.Box().FlexDirection_Column().Contains(field, after)
+		layout.End(r)
+	}
+	byId := func(id string) (gala.Snapshot, bool) {
+		return find(layout.Snapshot(), func(s gala.Snapshot) bool { return s.Id == id })
+	}
+	frame()
+	after, _ := byId("after")
+	if _, ok := byId("fruit/options"); ok {
+		t.Fatal("the list is declared while closed")
+	}
+
+	click(input, r, 5, 5, frame)
+	frame()
+	if _, ok := byId("fruit/options"); !ok {
+		t.Fatal("the list isn't declared after clicking the field")
+	}
+	if opened, _ := byId("after"); opened.Hash != after.Hash {
+		t.Errorf("the box after the select has the id %d while the list is open, want %d", opened.Hash, after.Hash)
+	}
+
+	pear, _ := byId("fruit/1")
+	click(input, r, int32(pear.X+pear.Width/2), int32(pear.Y+pear.Height/2), frame)
+	frame()
+	if selected != 1 {
+		t.Errorf("selected %d after clicking pear, want 1", selected)
+	}
+	// the rows read their clicks while the list is declared
+	frame()
+	if _, ok := byId("fruit/options"); ok {
+		t.Error("the list is still declared after picking an option")
+	}
+}
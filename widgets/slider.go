@@ -0,0 +1,152 @@
+package widgets
+
+import (
+	"fmt"
+	"gala/gala"
+	"math"
+)
+
+/*
+Slider picks a value between lo and hi by dragging the knob or clicking
+the track. Values snap to multiples of step above lo, 0 doesn't snap.
+
+While focused the arrow keys move it by a step (or 1% when step is 0),
+PageUp and PageDown by ten and Home and End to the ends.
+It returns its box and whether the value changed this frame.
+It's 200 pixels long by default.
+*/
+func (ui *UI) Slider(id string, value *float32, lo, hi, step float32) (*gala.Box, bool) {
+	return ui.slider(id, value, lo, hi, step, false)
+}
+
+// VSlider is a vertical Slider, 150 pixels long by default.
+func (ui *UI) VSlider(id string, value *float32, lo, hi, step float32) (*gala.Box, bool) {
+	return ui.slider(id, value, lo, hi, step, true)
+}
+
+func (ui *UI) slider(id string, value *float32, lo, hi, step float32, vertical bool) (*gala.Box, bool) {
+	l, t := ui.Layout, ui.Theme()
+	slider := l.Box().Id(id).Focusable().CapturePointer().Padding(2)
+	previous := *value
+	knob := float32(t.FontSize)
+
+	if l.Captured(slider.ID()) {
+		if r, ok := l.Rect(slider.ID()); ok {
+			x, y := l.MousePos()
+			// the middle of the knob follows the mouse
+			var offset, track float32
+			if vertical {
+				offset, track = float32(y-int32(r.Y)-2-int32(knob/2)), float32(r.Height)-4-knob
+			} else {
+				offset, track = float32(x-int32(r.X)-2-int32(knob/2)), float32(r.Width)-4-knob
+			}
+			ratio := trackRatio(offset, track)
+			if vertical {
+				ratio = 1 - ratio
+			}
+			*value = lo + ratio*(hi-lo)
+		}
+	}
+	if l.Focused(slider.ID()) {
+		small := step
+		if small == 0 {
+			small = (hi - lo) / 100
+		}
+		ui.stepKeys(value, small, lo, hi)
+	}
+	*value = snap(*value, lo, hi, step)
+
+	ratio := float32(0)
+	if hi > lo {
+		ratio = (*value - lo) / (hi - lo)
+	}
+	// the track on both sides of the knob shares the space by flex,
+	// 1 is added so neither side ends up without one
+	filled := l.Box().Flex(int16(ratio*1000) + 1).BackgroundColor(t.Accent)
+	empty := l.Box().Flex(int16((1-ratio)*1000) + 1).BackgroundColor(ui.surface(slider.ID()))
+	handle := l.Box().Size(knob, knob).BackgroundColor(t.Knob)
+	slider.BackgroundColor(ui.ring(slider.ID()))
+	if vertical {
+		return slider.
+			FlexDirection_Column().
+			Size(knob+4, 150).
+			Contains(empty.Width(knob), handle, filled.Width(knob)), *value != previous
+	}
+	return slider.
+		FlexDirection_Row().
+		Size(200, knob+4).
+		Contains(filled.Height(knob), handle, empty.Height(knob)), *value != previous
+}
+
+/*
+DragNumber shows a number that changes by speed for every pixel
+the mouse is dragged horizontally, clamped between lo and hi.
+format is used to print it, like "%.2f".
+
+While focused the arrow keys change it by speed.
+It returns its box and whether the value changed this frame.
+*/
+func (ui *UI) DragNumber(id string, value *float32, speed, lo, hi float32, format string) (*gala.Box, bool) {
+	l, t := ui.Layout, ui.Theme()
+	number := l.Box().Id(id).Focusable().CapturePointer().Padding(2)
+	previous := *value
+
+	if l.Captured(number.ID()) {
+		dx, _ := l.MouseDelta()
+		*value += float32(dx) * speed
+	}
+	if l.Focused(number.ID()) {
+		ui.stepKeys(value, speed, lo, hi)
+	}
+	*value = snap(*value, lo, hi, 0)
+
+	return number.
+		BackgroundColor(ui.ring(number.ID())).
+		Contains(
+			ui.label(fmt.Sprintf(format, *value)).
+				PaddingTop(t.Padding / 2).
+				PaddingBottom(t.Padding / 2).
+				PaddingLeft(t.Padding).
+				PaddingRight(t.Padding).
+				BackgroundColor(ui.surface(number.ID())),
+		), *value != previous
+}
+
+// stepKeys changes the value with the keyboard
+func (ui *UI) stepKeys(value *float32, step, lo, hi float32) {
+	l := ui.Layout
+	changes := [...]struct {
+		key   gala.Key
+		delta float32
+	}{
+		{gala.KeyLeft, -step},
+		{gala.KeyDown, -step},
+		{gala.KeyRight, step},
+		{gala.KeyUp, step},
+		{gala.KeyPageDown, -step * 10},
+		{gala.KeyPageUp, step * 10},
+		{gala.KeyHome, lo - hi},
+		{gala.KeyEnd, hi - lo},
+	}
+	for _, change := range changes {
+		if l.KeyPressed(change.key) {
+			l.ConsumeKey(change.key)
+			*value += change.delta
+		}
+	}
+}
+
+// snap clamps the value between lo and hi and rounds it to a multiple of step above lo
+func snap(value, lo, hi, step float32) float32 {
+	if step > 0 {
+		value = lo + float32(math.Round(float64((value-lo)/step)))*step
+	}
+	return min(max(value, lo), hi)
+}
+
+// trackRatio is how far along the track the offset is, between 0 and 1.
+// The track is at least a pixel long, sliders no longer than their knob
+// would divide by zero.
+func trackRatio(offset, track float32) float32 {
+	return min(max(offset/max(1, track), 0), 1)
+}
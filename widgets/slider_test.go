@@ -0,0 +1,49 @@
+package widgets
+
+import (
+	"gala/gala"
+	"testing"
+)
+
+func TestSliderDrag(t *testing.T) {
+	layout := gala.NewLayout(800, 600, 64)
+	input := &testInput{}
+	layout.UseInput(input)
+	ui := New(&layout, DefaultTheme)
+	r := &testRenderer{-1, -1}
+	value := float32(0)
+	frame := func() {
+		ui.Slider("volume", &value, 0, 100, 1)
+		layout.End(r)
+	}
+	frame()
+
+	// the middle of the track, the slider is 200 pixels long at the left edge
+	knob := int32(DefaultTheme.FontSize)
+	middle := 2 + knob/2 + (200-4-knob)/2
+	steps := []struct {
+		name string
+		x    int32
+		down bool
+		want float32
+	}{
+		{"pressed in the middle", middle, true, 50},
+		{"dragged past the end", 500, true, 100},
+		{"dragged past the start", -50, true, 0},
+		{"dragged back", middle, true, 50},
+		{"released", 500, false, 100},
+		{"moved after the release", middle, false, 100},
+	}
+	for _, step := range steps {
+		r.mouseX, r.mouseY = step.x, 5
+		input.mousePressed = step.down && !input.mouseDown
+		input.mouseDown = step.down
+		frame()
+		// the capture starts at the End of the frame the button went down
+		input.mousePressed = false
+		frame()
+		if value != step.want {
+			t.Errorf("%s: value %v, want %v", step.name, value, step.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package widgets
+
+import (
+	"gala/gala"
+	"strconv"
+	"time"
+)
+
+const (
+	dividerWidth     = 4
+	doubleClickDelay = 400 * time.Millisecond
+)
+
+// Pane of a SplitPane. Min is the smallest size in pixels
+// dragging a divider leaves it with.
+type Pane struct {
+	Box *gala.Box
+	Min float32
+}
+
+type splitState struct {
+	sizes     []float32 // flex weights, in pixels once a divider was dragged
+	collapsed []float32 // the size a collapsed pane had, 0 when it isn't
+	lastClick []time.Time
+}
+
+/*
+SplitPane lays the panes out in a row, separated by dividers that are
+dragged to resize the panes around them. Double clicking a divider
+collapses the pane before it, and restores it the next time.
+Sizes are kept per id and shared by flex, so they scale with the split.
+It fills its parent by default.
+*/
+func (ui *UI) SplitPane(id string, panes ...Pane) *gala.Box {
+	return ui.splitPane(id, false, panes)
+}
+
+// VSplitPane is a SplitPane that lays the panes out in a column.
+func (ui *UI) VSplitPane(id string, panes ...Pane) *gala.Box {
+	return ui.splitPane(id, true, panes)
+}
+
+func (ui *UI) splitPane(id string, vertical bool, panes []Pane) *gala.Box {
+	l, t := ui.Layout, ui.Theme()
+	split := l.Box().Id(id).Size(gala.Percent(100), gala.Percent(100)).FlexDirection_Row()
+	if vertical {
+		split.FlexDirection_Column()
+	}
+	s := gala.State[splitState](l, split.ID())
+	if len(s.sizes) != len(panes) {
+		s.sizes = make([]float32, len(panes))
+		s.collapsed = make([]float32, len(panes))
+		s.lastClick = make([]time.Time, len(panes))
+		for i := range s.sizes {
+			s.sizes[i] = 1000
+		}
+	}
+	// the size of the panes on the last frame, along the split
+	size := func(i int) (float32, bool) {
+		r, ok := l.Rect(panes[i].Box.ID())
+		if vertical {
+			return float32(r.Height), ok
+		}
+		return float32(r.Width), ok
+	}
+	// hidden panes, like collapsed ones, keep a size of 0
+	hidden := func(i int) bool {
+		return s.sizes[i] < 1
+	}
+	measure := func() bool {
+		for i := range panes {
+			if _, ok := size(i); !ok && !hidden(i) {
+				return false
+			}
+		}
+		for i := range panes {
+			if !hidden(i) {
+				s.sizes[i], _ = size(i)
+			}
+		}
+		return true
+	}
+
+	for i, pane := range panes {
+		pane.Box.Overflow_Hidden()
+		if i == 0 {
+			split.Contains(pane.Box)
+			continue
+		}
+		divider := l.Box().Id("divider" + strconv.Itoa(i)).CapturePointer()
+		if vertical {
+			divider.Size(gala.Percent(100), dividerWidth)
+		} else {
+			divider.Size(dividerWidth, gala.Percent(100))
+		}
+		before, after := i-1, i
+
+		if l.Captured(divider.ID()) && measure() {
+			dx, dy := l.MouseDelta()
+			delta := float32(dx)
+			if vertical {
+				delta = float32(dy)
+			}
+			delta = max(delta, panes[before].Min-s.sizes[before])
+			delta = min(delta, s.sizes[after]-panes[after].Min)
+			s.sizes[before] += delta
+			s.sizes[after] -= delta
+			s.collapsed[before] = 0
+		}
+		if l.Clicked(divider.ID()) {
+			now := l.Now()
+			if now.Sub(s.lastClick[i]) < doubleClickDelay && measure() {
+				s.toggle(before, after)
+				s.lastClick[i] = time.Time{}
+			} else {
+				s.lastClick[i] = now
+			}
+		}
+		divider.BackgroundColor(t.Surface)
+		if l.Captured(divider.ID()) || l.Hovered(divider.ID()) {
+			divider.BackgroundColor(t.Accent)
+		}
+		split.Contains(divider, pane.Box)
+	}
+
+	for i, pane := range panes {
+		if hidden(i) {
+			pane.Box.Display_None()
+			continue
+		}
+		pane.Box.Flex(int16(s.sizes[i]))
+		if vertical {
+			pane.Box.Width(gala.Percent(100))
+		} else {
+			pane.Box.Height(gala.Percent(100))
+		}
+	}
+	return split
+}
+
+// toggle collapses the pane before a divider into the one after it,
+// or restores it
+func (s *splitState) toggle(before, after int) {
+	if s.collapsed[before] == 0 {
+		s.collapsed[before] = s.sizes[before]
+		s.sizes[after] += s.sizes[before]
+		s.sizes[before] = 0
+		return
+	}
+	restored := min(s.collapsed[before], s.sizes[after])
+	s.sizes[before] += restored
+	s.sizes[after] -= restored
+	s.collapsed[before] = 0
+}
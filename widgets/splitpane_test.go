@@ -0,0 +1,41 @@
+package widgets
+
+import (
+	"gala/gala"
+	"testing"
+)
+
+func TestSplitPaneCollapse(t *testing.T) {
+	layout := gala.NewLayout(800, 600, 64)
+	ui := New(&layout, DefaultTheme)
+	var split, left, right *gala.Box
+	frame := func() {
+		left = layout.Box().Id("left")
+		right = layout.Box().Id("right")
+		split = ui.SplitPane("split", Pane{Box: left}, Pane{Box: right})
+		layout.End(testRenderer{-1, -1})
+	}
+	frame()
+	frame()
+	if r, _ := layout.Rect(left.ID()); r.Width != (800-dividerWidth)/2 {
+		t.Fatalf("left pane is %d wide, want half of the split", r.Width)
+	}
+
+	s := gala.State[splitState](&layout, split.ID())
+	s.toggle(0, 1)
+	for range 3 {
+		frame()
+	}
+	if r, ok := layout.Rect(left.ID()); ok {
+		t.Errorf("the collapsed pane is still laid out at %v", r)
+	}
+	if r, _ := layout.Rect(right.ID()); r.Width != 800-dividerWidth {
+		t.Errorf("right pane is %d wide, want all of the split", r.Width)
+	}
+
+	s.toggle(0, 1)
+	frame()
+	if r, _ := layout.Rect(left.ID()); r.Width != (800-dividerWidth)/2 {
+		t.Errorf("restored left pane is %d wide, want half of the split", r.Width)
+	}
+}
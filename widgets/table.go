@@ -0,0 +1,185 @@
+package widgets
+
+import (
+	"gala/gala"
+	"strconv"
+)
+
+// Column of a Table. Width is in pixels or a gala.Percent, when it's 0 the
+// column shares the width left with the other ones without a width, by Flex.
+// Dragging the right edge of its header resizes it.
+type Column struct {
+	Title    string
+	Width    float32
+	Flex     int16
+	Sortable bool
+}
+
+type SortOrder int8
+
+const (
+	Unsorted SortOrder = iota
+	Ascending
+	Descending
+)
+
+const (
+	resizeHandleWidth = 4
+	minColumnWidth    = 20
+)
+
+type tableState struct {
+	widths []float32 // set once a column is resized
+	sorted int
+	order  SortOrder
+}
+
+/*
+Table shows rows of text under a header, building only the visible rows.
+cell returns the text of a row in a column.
+
+Clicking the header of a sortable column sorts by it, clicking it again
+flips the order. onSort is called with the new order, the rows are expected
+to come sorted from cell after that. It may be nil.
+
+Clicking a row selects it, while focused the arrow keys, PageUp, PageDown,
+Home and End move the selection and keep it visible. -1 selects nothing.
+It returns its box and whether the selection changed this frame.
+It's 400 by 300 pixels by default.
+*/
+func (ui *UI) Table(id string, columns []Column, rows int, cell func(row, column int) string,
+	selected *int, onSort func(column int, order SortOrder)) (*gala.Box, bool) {
+	l, t := ui.Layout, ui.Theme()
+	table := l.Box().Id(id).Focusable().FlexDirection_Column().Padding(2).Size(400, 300)
+	s := gala.State[tableState](l, table.ID())
+	if len(s.widths) != len(columns) {
+		s.widths = make([]float32, len(columns))
+	}
+	previous := *selected
+	rowHeight := t.FontSize + t.Padding
+	inner := gala.Percent(100)
+	page := 10
+	if r, ok := l.Rect(table.ID()); ok {
+		inner = float32(r.Width - 4)
+		page = max(1, int(r.Height-rowHeight)/int(rowHeight))
+	}
+
+	moved := l.Focused(table.ID()) && ui.moveSelection(selected, rows, page)
+	*selected = min(*selected, rows-1)
+
+	header := l.Box().Id("header").FlexDirection_Row().Width(inner).BackgroundColor(t.Surface)
+	for c, column := range columns {
+		headerCell := ui.column(l.Box().Id(strconv.Itoa(c)), column, s.widths[c]).FlexDirection_Row()
+		title := ui.label(column.Title).
+			Id(id + "/title" + strconv.Itoa(c)).
+			Flex(1).
+			Height(float32(rowHeight)).
+			Overflow_Hidden().
+			PaddingTop(t.Padding / 2).
+			PaddingBottom(t.Padding / 2).
+			PaddingLeft(t.Padding / 2)
+		if column.Sortable {
+			title.BackgroundColor(ui.surface(title.ID()))
+		}
+		handle := l.Box().Id("resize").CapturePointer().Size(resizeHandleWidth, float32(rowHeight))
+		headerCell.Contains(title, handle)
+		header.Contains(headerCell)
+
+		if column.Sortable && l.Clicked(title.ID()) {
+			order := Ascending
+			if s.sorted == c && s.order == Ascending {
+				order = Descending
+			}
+			s.sorted, s.order = c, order
+			if onSort != nil {
+				onSort(c, s.order)
+			}
+		}
+		if column.Sortable && s.sorted == c {
+			switch s.order {
+			case Ascending:
+				title.Text(column.Title + "  ^")
+			case Descending:
+				title.Text(column.Title + "  v")
+			}
+		}
+		if l.Captured(handle.ID()) {
+			if s.widths[c] == 0 {
+				if r, ok := l.Rect(headerCell.ID()); ok {
+					s.widths[c] = float32(r.Width)
+				}
+			}
+			dx, _ := l.MouseDelta()
+			s.widths[c] = max(minColumnWidth, s.widths[c]+float32(dx))
+		}
+		if l.Captured(handle.ID()) || l.Hovered(handle.ID()) {
+			handle.BackgroundColor(t.Accent)
+		}
+	}
+
+	list := l.VirtualList("rows", rows, rowHeight, func(i int) *gala.Box {
+		row := l.Box().Id(strconv.Itoa(i)).FlexDirection_Row().Width(gala.Percent(100))
+		if l.Clicked(row.ID()) {
+			*selected = i
+		}
+		switch {
+		case i == *selected:
+			row.BackgroundColor(t.Accent)
+		case l.Hovered(row.ID()):
+			row.BackgroundColor(t.SurfaceHovered)
+		}
+		for c, column := range columns {
+			row.Contains(
+				ui.column(ui.label(cell(i, c)), column, s.widths[c]).
+					Height(float32(rowHeight)).
+					Overflow_Hidden().
+					PaddingTop(t.Padding / 2).
+					PaddingLeft(t.Padding / 2),
+			)
+		}
+		return row
+	}).Width(inner).Flex(1)
+	if moved {
+		l.ScrollListTo(list.ID(), *selected)
+	}
+
+	return table.
+		BackgroundColor(ui.ring(table.ID())).
+		Contains(header, list), *selected != previous
+}
+
+// column sizes a header or a cell box like the column, or to its resized width
+func (ui *UI) column(box *gala.Box, column Column, resized float32) *gala.Box {
+	switch {
+	case resized > 0:
+		return box.Width(resized)
+	case column.Width != 0:
+		return box.Width(column.Width)
+	}
+	return box.Flex(max(1, column.Flex))
+}
+
+// moveSelection moves the selected row with the keyboard and reports whether it did
+func (ui *UI) moveSelection(selected *int, rows, page int) bool {
+	l := ui.Layout
+	moves := [...]struct {
+		key   gala.Key
+		delta int
+	}{
+		{gala.KeyUp, -1},
+		{gala.KeyDown, 1},
+		{gala.KeyPageUp, -page},
+		{gala.KeyPageDown, page},
+		{gala.KeyHome, -rows},
+		{gala.KeyEnd, rows},
+	}
+	moved := false
+	for _, move := range moves {
+		if l.KeyPressed(move.key) {
+			l.ConsumeKey(move.key)
+			*selected = max(0, min(rows-1, *selected+move.delta))
+			moved = true
+		}
+	}
+	return moved
+}
@@ -0,0 +1,166 @@
+package widgets
+
+import "gala/gala"
+
+// Tab of a Tabs widget, Closable tabs have a button to close them.
+type Tab struct {
+	Id       string
+	Title    string
+	Closable bool
+}
+
+type tabsState struct {
+	selected string
+	index    int   // of the selected tab, to select a neighbor when it's closed
+	scroll   int16 // of the header strip
+	reveal   bool  // scroll the selected header into view
+}
+
+/*
+Tabs shows a header for every tab and the content of the selected one,
+content is only called for it. The selection is kept by the id of the tab.
+
+When the headers don't fit they scroll with the mouse wheel, and the
+selected one is scrolled into view when it changes. While the headers are
+focused Left and Right switch tabs, Home and End go to the first and last
+one and Delete closes a closable tab. Clicking the close button of a tab
+or pressing Delete calls onClose with its id, it's up to the caller to
+remove it. onClose may be nil.
+It returns its box and the id of the selected tab.
+It's 400 by 300 pixels by default.
+*/
+func (ui *UI) Tabs(id string, tabs []Tab, content func(tab Tab) *gala.Box, onClose func(id string)) (*gala.Box, string) {
+	return ui.tabs(id, tabs, nil, content, onClose, nil)
+}
+
+// tabs is Tabs that keeps the selection in selectedId when it isn't nil,
+// and calls onDrag while a header is dragged when it isn't nil
+func (ui *UI) tabs(id string, tabs []Tab, selectedId *string, content func(tab Tab) *gala.Box,
+	onClose func(id string), onDrag func(id string)) (*gala.Box, string) {
+	l, t := ui.Layout, ui.Theme()
+	box := l.Box().Id(id).FlexDirection_Column().Size(400, 300)
+	s := gala.State[tabsState](l, box.ID())
+	if selectedId == nil {
+		selectedId = &s.selected
+	}
+	strip := l.Box().Id("strip").Focusable().Scrollable().Overflow_Hidden().Width(gala.Percent(100))
+	headers := l.Box().Id("headers").FlexDirection_Row()
+	if len(tabs) == 0 {
+		*selectedId = ""
+		return box.Contains(strip.Contains(headers)), ""
+	}
+
+	selected := -1
+	for i, tab := range tabs {
+		if tab.Id == *selectedId {
+			selected = i
+		}
+	}
+	if selected == -1 {
+		// the selected tab was closed, select the one that took its place
+		selected = max(0, min(s.index, len(tabs)-1))
+	}
+	closing := ""
+	if l.Focused(strip.ID()) {
+		moves := [...]struct {
+			key gala.Key
+			to  int
+		}{
+			{gala.KeyLeft, selected - 1},
+			{gala.KeyRight, selected + 1},
+			{gala.KeyHome, 0},
+			{gala.KeyEnd, len(tabs) - 1},
+		}
+		for _, move := range moves {
+			if l.KeyPressed(move.key) {
+				l.ConsumeKey(move.key)
+				selected = max(0, min(move.to, len(tabs)-1))
+			}
+		}
+		if l.KeyPressed(gala.KeyDelete) && tabs[selected].Closable {
+			l.ConsumeKey(gala.KeyDelete)
+			closing = tabs[selected].Id
+		}
+	}
+
+	boxes := make([]*gala.Box, len(tabs))
+	for i, tab := range tabs {
+		header := l.Box().Id(tab.Id).FlexDirection_Row()
+		title := ui.label(tab.Title).
+			PaddingTop(t.Padding / 2).
+			PaddingBottom(t.Padding / 2).
+			PaddingLeft(t.Padding).
+			PaddingRight(t.Padding)
+		header.Contains(title)
+		if tab.Closable {
+			closeButton := ui.label("x").
+				Id(id + "/" + tab.Id + "/close").
+				PaddingTop(t.Padding / 2).
+				PaddingBottom(t.Padding / 2).
+				PaddingRight(t.Padding / 2).
+				PaddingLeft(t.Padding / 2)
+			closeButton.BackgroundColor(ui.surface(closeButton.ID()))
+			header.Contains(closeButton)
+			if l.Clicked(closeButton.ID()) {
+				closing = tab.Id
+			}
+		}
+		if l.Clicked(header.ID()) && closing != tab.Id {
+			selected = i
+		}
+		if onDrag != nil {
+			header.CapturePointer()
+			if l.Captured(header.ID()) {
+				onDrag(tab.Id)
+			}
+		}
+		headers.Contains(header)
+		boxes[i] = header
+	}
+	for i, header := range boxes {
+		switch {
+		case i == selected:
+			header.BackgroundColor(t.Accent)
+		case l.Hovered(header.ID()):
+			header.BackgroundColor(t.SurfaceHovered)
+		}
+	}
+	if tabs[selected].Id != *selectedId {
+		*selectedId, s.reveal = tabs[selected].Id, true
+	}
+	s.index = selected
+	ui.scrollTabs(s, strip, headers, boxes[selected])
+	if closing != "" && onClose != nil {
+		onClose(closing)
+	}
+
+	panel := l.Box().Id("panel").Flex(1).Width(gala.Percent(100)).Contains(content(tabs[selected]))
+	return box.Contains(
+		strip.
+			BackgroundColor(ui.ring(strip.ID())).
+			Padding(2).
+			Contains(headers.BackgroundColor(t.Surface)),
+		panel,
+	), *selectedId
+}
+
+// scrollTabs scrolls the headers with the wheel and to the selected one,
+// using their rects of the last frame
+func (ui *UI) scrollTabs(s *tabsState, strip, headers, selected *gala.Box) {
+	l := ui.Layout
+	stripRect, ok := l.Rect(strip.ID())
+	headersRect, ok2 := l.Rect(headers.ID())
+	if !ok || !ok2 {
+		return
+	}
+	visible := stripRect.Width - 4
+	s.scroll -= int16(l.ConsumeWheel(strip.ID()) * float32(ui.Theme().FontSize) * 3)
+	if r, ok := l.Rect(selected.ID()); ok && s.reveal {
+		x := r.X - headersRect.X
+		s.scroll = min(s.scroll, x)
+		s.scroll = max(s.scroll, x+r.Width-visible)
+		s.reveal = false
+	}
+	s.scroll = max(0, min(s.scroll, headersRect.Width-visible))
+	headers.MarginLeft(-s.scroll)
+}
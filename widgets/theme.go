@@ -0,0 +1,61 @@
+package widgets
+
+import "image/color"
+
+// Theme holds the colors and sizes widgets are drawn with.
+type Theme struct {
+	Text           color.RGBA
+	Surface        color.RGBA // background of controls
+	SurfaceHovered color.RGBA
+	SurfacePressed color.RGBA
+	Accent         color.RGBA // checked and selected controls
+	Knob           color.RGBA // the handle of toggles
+	FocusRing      color.RGBA
+
+	FontSize int16
+	Padding  int16
+}
+
+var DefaultTheme = Theme{
+	Text:           color.RGBA{230, 230, 235, 255},
+	Surface:        color.RGBA{52, 52, 58, 255},
+	SurfaceHovered: color.RGBA{66, 66, 74, 255},
+	SurfacePressed: color.RGBA{40, 40, 45, 255},
+	Accent:         color.RGBA{88, 101, 242, 255},
+	Knob:           color.RGBA{240, 240, 245, 255},
+	FocusRing:      color.RGBA{140, 150, 255, 255},
+
+	FontSize: 18,
+	Padding:  8,
+}
+
+// Theme returns what the widgets are drawn with: the tokens of the theme
+// the layout uses right now, and the ones of Base it doesn't have.
+func (ui *UI) Theme() Theme {
+	theme := ui.Layout.Theme()
+	t := ui.Base
+	colors := [...]struct {
+		token string
+		color *color.RGBA
+	}{
+		{"text", &t.Text},
+		{"surface", &t.Surface},
+		{"surfaceHovered", &t.SurfaceHovered},
+		{"surfacePressed", &t.SurfacePressed},
+		{"accent", &t.Accent},
+		{"knob", &t.Knob},
+		{"focusRing", &t.FocusRing},
+	}
+	for _, c := range colors {
+		if token, ok := theme.Colors[c.token]; ok {
+			*c.color = token
+		}
+	}
+	if size, ok := theme.Fonts["body"]; ok {
+		t.FontSize = size
+	}
+	if padding, ok := theme.Spacing["medium"]; ok {
+		t.Padding = padding
+	}
+	return t
+}
@@ -0,0 +1,27 @@
+package widgets
+
+import (
+	"gala/gala"
+	"testing"
+)
+
+func TestThemeFollowsLayout(t *testing.T) {
+	layout := gala.NewLayout(800, 600, 64)
+	ui := New(&layout, DefaultTheme)
+	if got := ui.Theme(); got != DefaultTheme {
+		t.Errorf("theme %v with the dark layout theme, want the default one %v", got, DefaultTheme)
+	}
+
+	light := gala.LightTheme()
+	layout.UseTheme(light)
+	if got, want := ui.Theme().Surface, light.Colors["surface"]; got != want {
+		t.Errorf("surface %v after switching to the light theme, want %v", got, want)
+	}
+
+	// tokens the theme doesn't have come from Base
+	sparse := gala.Theme{Name: "sparse", Fonts: map[string]int16{"body": 30}}
+	layout.UseTheme(sparse)
+	if got := ui.Theme(); got.FontSize != 30 || got.Text != DefaultTheme.Text || got.Padding != DefaultTheme.Padding {
+		t.Errorf("theme %v, want the font size of the layout theme and the rest of Base", got)
+	}
+}
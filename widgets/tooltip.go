@@ -0,0 +1,17 @@
+package widgets
+
+import "gala/gala"
+
+// Tooltip shows the text in a small themed box next to the widget,
+// once it has been hovered for the layout's tooltip delay.
+func (ui *UI) Tooltip(widget *gala.Box, text string) *gala.Box {
+	t := ui.Theme()
+	return widget.Tooltip(func(l *gala.Layout) *gala.Box {
+		return ui.label(text).
+			PaddingTop(t.Padding / 2).
+			PaddingBottom(t.Padding / 2).
+			PaddingLeft(t.Padding).
+			PaddingRight(t.Padding).
+			BackgroundColor(t.Surface)
+	})
+}
@@ -0,0 +1,182 @@
+package widgets
+
+import "gala/gala"
+
+// TreeNode is a node of a TreeView. Children is only called while the node
+// is expanded, it's nil for leaves.
+type TreeNode struct {
+	Id       string
+	Text     string
+	Children func() []TreeNode
+}
+
+// a node with the children it has this frame
+type treeItem struct {
+	node     TreeNode
+	path     string
+	parent   *treeItem
+	children []*treeItem
+	expanded bool
+}
+
+type treeState struct {
+	expanded map[string]bool // by path
+}
+
+const treeIndent = 16
+
+/*
+TreeView shows nested nodes that expand and collapse by clicking their arrow.
+Nodes are identified by their path, the ids from the root joined by "/", so
+ids must be unique among siblings and not contain "/". Whether a node is
+expanded is kept by its path, collapsed nodes don't build their children.
+
+Clicking a node selects its path. While focused Up and Down move the
+selection, Right expands it or moves to its first child, Left collapses it
+or moves to its parent, Enter and Space toggle it.
+It returns its box and whether the selection changed this frame.
+*/
+func (ui *UI) TreeView(id string, roots []TreeNode, selected *string) (*gala.Box, bool) {
+	l := ui.Layout
+	tree := l.Box().Id(id).Focusable().FlexDirection_Column().Padding(2)
+	s := gala.State[treeState](l, tree.ID())
+	if s.expanded == nil {
+		s.expanded = make(map[string]bool)
+	}
+	previous := *selected
+
+	items, visible := s.expand(roots)
+	if l.Focused(tree.ID()) && ui.navigateTree(s, visible, selected) {
+		items, _ = s.expand(roots)
+	}
+
+	nodes := l.Box().FlexDirection_Column()
+	for _, item := range items {
+		nodes.Contains(ui.treeNode(s, item, selected))
+	}
+	return tree.
+		BackgroundColor(ui.ring(tree.ID())).
+		Contains(nodes.BackgroundColor(ui.Theme().Surface)), *selected != previous
+}
+
+// expand builds the items of the expanded nodes
+// and returns them with the visible ones in order
+func (s *treeState) expand(roots []TreeNode) ([]*treeItem, []*treeItem) {
+	var visible []*treeItem
+	var build func(nodes []TreeNode, parent *treeItem) []*treeItem
+	build = func(nodes []TreeNode, parent *treeItem) []*treeItem {
+		items := make([]*treeItem, len(nodes))
+		for i, node := range nodes {
+			item := &treeItem{node: node, path: node.Id, parent: parent}
+			if parent != nil {
+				item.path = parent.path + "/" + node.Id
+			}
+			item.expanded = node.Children != nil && s.expanded[item.path]
+			visible = append(visible, item)
+			if item.expanded {
+				item.children = build(node.Children(), item)
+			}
+			items[i] = item
+		}
+		return items
+	}
+	return build(roots, nil), visible
+}
+
+// treeNode builds the row of the item and its expanded children
+func (ui *UI) treeNode(s *treeState, item *treeItem, selected *string) *gala.Box {
+	l, t := ui.Layout, ui.Theme()
+	node := l.Box().Id(item.node.Id).FlexDirection_Column()
+	row := l.Box().Id(item.path + "/row").FlexDirection_Row()
+	arrow := ui.label("").Id(item.path+"/arrow").Size(treeIndent, float32(t.FontSize))
+	switch {
+	case item.node.Children == nil:
+	case item.expanded:
+		arrow.Text("v")
+	default:
+		arrow.Text(">")
+	}
+	row.Contains(arrow, ui.label(item.node.Text).PaddingRight(t.Padding))
+	node.Contains(row)
+
+	if item.node.Children != nil && l.Clicked(arrow.ID()) {
+		s.expanded[item.path] = !item.expanded
+	} else if l.Clicked(row.ID()) {
+		*selected = item.path
+	}
+	switch {
+	case *selected == item.path:
+		row.BackgroundColor(t.Accent)
+	case l.Hovered(row.ID()):
+		row.BackgroundColor(t.SurfaceHovered)
+	}
+
+	if !item.expanded || len(item.children) == 0 {
+		return node
+	}
+	// the guide runs along the children, under the arrow
+	guide := l.Box().
+		Width(1).
+		Height(gala.Percent(100)).
+		MarginLeft(treeIndent / 2).
+		MarginRight(treeIndent/2 - 1).
+		BackgroundColor(t.SurfaceHovered)
+	children := l.Box().FlexDirection_Column()
+	for _, child := range item.children {
+		children.Contains(ui.treeNode(s, child, selected))
+	}
+	return node.Contains(l.Box().FlexDirection_Row().Contains(guide, children))
+}
+
+// navigateTree moves the selection and expands nodes with the keyboard,
+// it reports whether a node was expanded or collapsed
+func (ui *UI) navigateTree(s *treeState, visible []*treeItem, selected *string) bool {
+	l := ui.Layout
+	if len(visible) == 0 {
+		return false
+	}
+	current := -1
+	for i, item := range visible {
+		if item.path == *selected {
+			current = i
+		}
+	}
+	toggled := false
+	consume := func(key gala.Key) bool {
+		if l.KeyPressed(key) {
+			l.ConsumeKey(key)
+			return true
+		}
+		return false
+	}
+	if consume(gala.KeyUp) {
+		current = max(0, current-1)
+	}
+	if consume(gala.KeyDown) {
+		current = min(len(visible)-1, current+1)
+	}
+	if current == -1 {
+		return false
+	}
+	item := visible[current]
+	switch {
+	case consume(gala.KeyRight):
+		if item.expanded && len(item.children) > 0 {
+			item = item.children[0]
+		} else if item.node.Children != nil {
+			s.expanded[item.path], toggled = true, true
+		}
+	case consume(gala.KeyLeft):
+		if item.expanded {
+			s.expanded[item.path], toggled = false, true
+		} else if item.parent != nil {
+			item = item.parent
+		}
+	case consume(gala.KeyEnter) || consume(gala.KeySpace):
+		if item.node.Children != nil {
+			s.expanded[item.path], toggled = !item.expanded, true
+		}
+	}
+	*selected = item.path
+	return toggled
+}
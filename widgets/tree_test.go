@@ -0,0 +1,39 @@
+package widgets
+
+import (
+	"gala/gala"
+	"testing"
+)
+
+func TestTreeGuide(t *testing.T) {
+	layout := gala.NewLayout(800, 600, 256)
+	ui := New(&layout, DefaultTheme)
+	leaf := func(id string) TreeNode { return TreeNode{Id: id, Text: id} }
+	roots := []TreeNode{{
+		Id:   "a",
+		Text: "a",
+		Children: func() []TreeNode {
+			return []TreeNode{leaf("b"), leaf("c"), leaf("d")}
+		},
+	}}
+	selected := ""
+	frame := func() gala.ID {
+		tree, _ := ui.TreeView("tree", roots, &selected)
+		layout.End(testRenderer{-1, -1})
+		return tree.ID()
+	}
+	id := frame()
+	gala.State[treeState](&layout, id).expanded = map[string]bool{"a": true}
+	frame()
+
+	snapshot := layout.Snapshot()
+	guide, ok := find(snapshot, func(s gala.Snapshot) bool { return s.Width == 1 })
+	if !ok {
+		t.Fatal("the expanded node has no guide")
+	}
+	row, _ := find(snapshot, func(s gala.Snapshot) bool { return s.Text == "b" })
+	// the guide runs along the three children
+	if want := 3 * row.Height; guide.Height != want {
+		t.Errorf("guide is %d pixels high, want %d", guide.Height, want)
+	}
+}
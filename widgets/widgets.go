@@ -0,0 +1,83 @@
+/*
+Package widgets has the controls every gala app needs,
+built out of gala boxes and styled with the theme of the layout.
+
+	ui := widgets.New(&layout, widgets.DefaultTheme)
+	save, clicked := ui.Button("save", "Save")
+	if clicked {
+		...
+	}
+	layout.Box().Contains(save)
+
+Widgets keep their hover, pressed and focused state by the id of their box,
+so every widget needs an id that's unique among its siblings. The boxes
+inside them that react to the pointer have Ids made from it, and boxes
+passed to them, like the target of a ContextMenu, need an Id too: before
+End a box without an Id has the id its pool slot had on the last frame,
+which belongs to another box once boxes declared before it come and go.
+*/
+package widgets
+
+import (
+	"gala/gala"
+	"image/color"
+)
+
+// UI builds widgets into a layout, styled with the theme of the layout.
+type UI struct {
+	Layout *gala.Layout
+	Base   Theme // for the tokens the theme of the layout doesn't have
+}
+
+func New(layout *gala.Layout, base Theme) *UI {
+	return &UI{Layout: layout, Base: base}
+}
+
+var transparent = color.RGBA{}
+
+// activated reports whether the box was clicked, or Enter, Space or the
+// gamepad's confirm button was pressed while it had focus.
+func (ui *UI) activated(id gala.ID) bool {
+	l := ui.Layout
+	if l.Clicked(id) {
+		return true
+	}
+	if !l.Focused(id) {
+		return false
+	}
+	for _, key := range [...]gala.Key{gala.KeyEnter, gala.KeySpace} {
+		if l.KeyPressed(key) {
+			l.ConsumeKey(key)
+			return true
+		}
+	}
+	return l.GamepadPressed(gala.GamepadFaceDown)
+}
+
+// the outline drawn around a focused widget
+func (ui *UI) ring(id gala.ID) color.RGBA {
+	if ui.Layout.Focused(id) {
+		return ui.Theme().FocusRing
+	}
+	return transparent
+}
+
+// the background of a control, depending on its pointer state
+func (ui *UI) surface(id gala.ID) color.RGBA {
+	switch {
+	case ui.Layout.Pressed(id):
+		return ui.Theme().SurfacePressed
+	case ui.Layout.Hovered(id):
+		return ui.Theme().SurfaceHovered
+	}
+	return ui.Theme().Surface
+}
+
+// a text box with the theme's font
+func (ui *UI) label(text string) *gala.Box {
+	t := ui.Theme()
+	return ui.Layout.Box().
+		Text(text).
+		FontSize(t.FontSize).
+		TextColor(t.Text)
+}